@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/erwaen/type-glish/internal/cefr"
+	"github.com/erwaen/type-glish/internal/config"
+	"github.com/erwaen/type-glish/internal/eval"
+	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/llm"
+)
+
+// runCalibrate implements the "calibrate" command, which grades the anchor
+// sentences of the eval corpus and stores a score calibration for the
+// configured provider and model
+func runCalibrate(args []string) error {
+	fs := flag.NewFlagSet("calibrate", flag.ContinueOnError)
+	repeats := fs.Int("repeats", 2, "calls per anchor sentence")
+	provider := fs.String("provider", "", "provider to calibrate: llamacpp or gemini (default from config)")
+	model := fs.String("model", "", "Gemini model to calibrate (default from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *repeats < 1 {
+		return fmt.Errorf("repeats must be at least 1")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if *provider != "" {
+		cfg.Provider = *provider
+	}
+	if *model != "" {
+		cfg.GeminiModel = *model
+	}
+
+	if err := loadPrompts(); err != nil {
+		return err
+	}
+
+	items, err := eval.LoadCorpus()
+	if err != nil {
+		return err
+	}
+	anchors := eval.Anchors(items)
+
+	resolveLocalModel()
+	client := llm.NewClient(game.NewProvider(cfg))
+	fmt.Printf("Calibrating %s on %d anchor sentences\n", client.ProviderName(), len(anchors))
+
+	res := eval.Run(client, anchors, *repeats, cefr.DefaultLevel, func(done, total int) {
+		fmt.Printf("\r  %d/%d calls", done, total)
+	})
+	fmt.Println()
+
+	return saveCalibration(res, anchors)
+}
+
+// saveCalibration fits and stores the calibration of an eval result
+func saveCalibration(res eval.Result, items []eval.Item) error {
+	cal, err := res.Calibration(items)
+	if err != nil {
+		return err
+	}
+	if err := llm.SaveCalibration(cal); err != nil {
+		return err
+	}
+	fmt.Printf("Saved calibration for %s: score × %.2f %+.2f (%d anchors)\n", cal.Model, cal.Scale, cal.Offset, cal.Anchors)
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/erwaen/type-glish/internal/cefr"
+	"github.com/erwaen/type-glish/internal/config"
+	"github.com/erwaen/type-glish/internal/eval"
+	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/llm"
+)
+
+// runEval implements the "eval" command, which grades the bundled corpus
+// with the configured provider and writes a report comparing every model
+// evaluated so far
+func runEval(args []string) error {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	dir := fs.String("dir", ".", "directory for the per-model results and the report")
+	repeats := fs.Int("repeats", 3, "calls per sentence, to measure score variance")
+	provider := fs.String("provider", "", "provider to evaluate: llamacpp or gemini (default from config)")
+	model := fs.String("model", "", "Gemini model to evaluate (default from config)")
+	limit := fs.Int("limit", 0, "only evaluate the first n sentences")
+	calibrate := fs.Bool("calibrate", false, "also store a score calibration fitted to this run")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *repeats < 1 {
+		return fmt.Errorf("repeats must be at least 1")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if *provider != "" {
+		cfg.Provider = *provider
+	}
+	if *model != "" {
+		cfg.GeminiModel = *model
+	}
+
+	if err := loadPrompts(); err != nil {
+		return err
+	}
+
+	items, err := eval.LoadCorpus()
+	if err != nil {
+		return err
+	}
+	if *limit > 0 && *limit < len(items) {
+		items = items[:*limit]
+	}
+
+	resolveLocalModel()
+	client := llm.NewClient(game.NewProvider(cfg))
+	fmt.Printf("Evaluating %s on %d sentences, %d calls each\n", client.ProviderName(), len(items), *repeats)
+
+	res := eval.Run(client, items, *repeats, cefr.DefaultLevel, func(done, total int) {
+		fmt.Printf("\r  %d/%d calls", done, total)
+	})
+	fmt.Println()
+
+	path, err := res.Save(*dir)
+	if err != nil {
+		return err
+	}
+	results, err := eval.LoadResults(*dir)
+	if err != nil {
+		return err
+	}
+	report, err := eval.WriteReport(*dir, results)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Agreement %.0f%%, JSON valid %.0f%%, score spread %.2f, mean latency %.0f ms, %d errors\n",
+		res.Agreement*100, res.JSONValidity*100, res.ScoreStdDev, res.MeanLatencyMS, res.Errors)
+	fmt.Println("  " + path)
+	fmt.Println("  " + report)
+
+	if *calibrate {
+		return saveCalibration(res, items)
+	}
+	return nil
+}
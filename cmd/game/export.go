@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/erwaen/type-glish/internal/export"
+	"github.com/erwaen/type-glish/internal/review"
+	"github.com/erwaen/type-glish/internal/vocab"
+)
+
+// runExport implements the "export" command, which writes the collected
+// corrections and vocabulary to CSV and Anki files
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	dir := fs.String("dir", ".", "directory to write the export files to")
+	format := fs.String("format", export.FormatAll, "export format: csv, anki or all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	deck, err := review.LoadDeck()
+	if err != nil {
+		return err
+	}
+	book, err := vocab.LoadBook()
+	if err != nil {
+		return err
+	}
+
+	paths, err := export.Write(*dir, *format, deck.Cards, book.Entries)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d corrections and %d words:\n", len(deck.Cards), len(book.Entries))
+	for _, p := range paths {
+		fmt.Println("  " + p)
+	}
+	return nil
+}
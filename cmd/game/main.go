@@ -8,10 +8,35 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
+	"github.com/erwaen/type-glish/internal/llm"
 	"github.com/erwaen/type-glish/internal/tui"
 )
 
 func main() {
+	// Subcommands run without the TUI
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			fmt.Println("export failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "eval" {
+		if err := runEval(os.Args[2:]); err != nil {
+			fmt.Println("eval failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "calibrate" {
+		if err := runCalibrate(os.Args[2:]); err != nil {
+			fmt.Println("calibrate failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Because tea doesn't now allow me to see prints, I can store in a file to check it
 	f, err := tea.LogToFile("debug.log", "debug")
 	if err != nil {
@@ -20,6 +45,13 @@ func main() {
 	}
 	defer f.Close()
 
+	// Custom prompt templates must render before the game starts, otherwise
+	// every grading call would fail
+	if err := loadPrompts(); err != nil {
+		fmt.Println("invalid prompt template:", err)
+		os.Exit(1)
+	}
+
 	// Load Configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -27,6 +59,17 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Load UI translations, including custom ones from the config directory
+	if dir, err := config.GetDataPath("locales"); err == nil {
+		if err := i18n.LoadDir(dir); err != nil {
+			log.Printf("Error loading locales: %v\n", err)
+		}
+	}
+	i18n.SetLocale(cfg.Locale)
+
+	// Look up the local model before the UI starts, never from inside it
+	resolveLocalModel()
+
 	// creates the game data and setup the llm provider
 	ctx := game.NewContext(cfg)
 
@@ -37,4 +80,36 @@ func main() {
 		fmt.Printf("Alas, there's been an error: %v", err)
 		os.Exit(1)
 	}
+
+	if summary := ctx.Metrics.Summary(); summary != "" {
+		log.Printf("LLM metrics this session:\n%s", summary)
+	}
+}
+
+// loadPrompts loads the prompt template overrides from the config
+// directory and logs the version of every prompt in use
+func loadPrompts() error {
+	dir, err := config.GetDataPath("prompts")
+	if err != nil {
+		return nil
+	}
+	if err := llm.LoadPromptDir(dir); err != nil {
+		return err
+	}
+	for _, p := range llm.Prompts() {
+		log.Printf("Prompt %s from %s", p.Tag(), p.Source)
+	}
+	return nil
+}
+
+// resolveLocalModel asks llama.cpp which model it serves, so cache keys and
+// calibrations use "llamacpp/<model>". A server that is down is asked
+// again on the first call.
+func resolveLocalModel() {
+	model, err := llm.ResolveLlamaCppModel()
+	if err != nil {
+		log.Printf("llama.cpp model unknown for now: %v", err)
+		return
+	}
+	log.Printf("llama.cpp serves %s", model)
 }
@@ -0,0 +1,152 @@
+package adventure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/erwaen/type-glish/internal/config"
+)
+
+const journalFileName = "adventure.json"
+
+// maxEntries bounds the journal, oldest entries are dropped first
+const maxEntries = 200
+
+// Scoring
+const (
+	PointsPerScore = 10 // Points for each grammar score point
+	StreakScore    = 7  // Lowest score that keeps a streak going
+	StreakBonus    = 5  // Bonus points per action of the streak before this one
+	MaxStreakBonus = 25
+)
+
+// Opening is the first scene of a new Free Adventure
+const Opening = "You stand at the gates of the Kingdom of Lexicon, where words have power. No enemy waits for you here, only a world that bends to well-made sentences. What do you do?"
+
+// Entry is one action of the Free Adventure
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Input     string    `json:"input"`
+	Corrected string    `json:"corrected,omitempty"`
+	Score     int       `json:"score"`
+	Points    int       `json:"points"`
+	Streak    int       `json:"streak"` // Streak after this action
+	DMComment string    `json:"dm_comment,omitempty"`
+	Outcome   string    `json:"outcome,omitempty"`
+	Offline   bool      `json:"offline,omitempty"`   // Graded by the offline grader
+	Repeat    bool      `json:"repeat,omitempty"`    // Repeated an earlier action, no points
+	Injection bool      `json:"injection,omitempty"` // Tried to rewrite the rules, no points
+}
+
+// Journal is the persisted story and score of the Free Adventure
+type Journal struct {
+	Entries     []Entry `json:"entries"`
+	TotalPoints int     `json:"total_points"`
+	Actions     int     `json:"actions"`
+	Streak      int     `json:"streak"` // Current run of actions scored StreakScore or more
+	BestStreak  int     `json:"best_streak"`
+}
+
+// LoadJournal reads the journal from the config directory, returning an
+// empty journal if none has been saved yet
+func LoadJournal() (*Journal, error) {
+	path, err := config.GetDataPath(journalFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Journal{}, nil
+		}
+		return nil, err
+	}
+
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("failed to parse adventure journal: %w", err)
+	}
+	return &j, nil
+}
+
+// Save writes the journal to the config directory
+func (j *Journal) Save() error {
+	path, err := config.GetDataPath(journalFileName)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal adventure journal: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// Points returns the points of an action given the streak before it
+func Points(score, streak int) int {
+	return score*PointsPerScore + min(streak*StreakBonus, MaxStreakBonus)
+}
+
+// Record scores an entry, adds it to the journal and returns it with its
+// points and streak filled in. Repeats and injection attempts score no
+// points and break the streak.
+func (j *Journal) Record(e Entry) Entry {
+	switch {
+	case e.Repeat || e.Injection:
+		e.Points = 0
+		j.Streak = 0
+	case e.Score < StreakScore:
+		e.Points = Points(e.Score, 0)
+		j.Streak = 0
+	default:
+		e.Points = Points(e.Score, j.Streak)
+		j.Streak++
+	}
+	e.Streak = j.Streak
+	j.BestStreak = max(j.BestStreak, j.Streak)
+	j.TotalPoints += e.Points
+	j.Actions++
+
+	j.Entries = append(j.Entries, e)
+	if len(j.Entries) > maxEntries {
+		j.Entries = j.Entries[len(j.Entries)-maxEntries:]
+	}
+	return e
+}
+
+// Inputs returns the last n player inputs, newest last
+func (j *Journal) Inputs(n int) []string {
+	var inputs []string
+	for _, e := range j.Entries[max(0, len(j.Entries)-n):] {
+		inputs = append(inputs, e.Input)
+	}
+	return inputs
+}
+
+// Recap returns the outcomes of the last n actions, oldest first, so the
+// DM can keep the story consistent
+func (j *Journal) Recap(n int) string {
+	var lines []string
+	for _, e := range j.Entries[max(0, len(j.Entries)-n):] {
+		if e.Outcome != "" {
+			lines = append(lines, "- "+e.Outcome)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Scene returns where the story stands: the last outcome, or the opening
+func (j *Journal) Scene() string {
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		if j.Entries[i].Outcome != "" {
+			return j.Entries[i].Outcome
+		}
+	}
+	return Opening
+}
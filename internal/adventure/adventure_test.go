@@ -0,0 +1,164 @@
+package adventure
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestPoints(t *testing.T) {
+	tests := []struct {
+		score, streak, want int
+	}{
+		{score: 0, streak: 0, want: 0},
+		{score: 7, streak: 0, want: 70},
+		{score: 8, streak: 1, want: 85},
+		{score: 10, streak: 4, want: 120},
+		{score: 10, streak: 5, want: 125},
+		{score: 10, streak: 50, want: 100 + MaxStreakBonus},
+	}
+	for _, tt := range tests {
+		if got := Points(tt.score, tt.streak); got != tt.want {
+			t.Errorf("Points(%d, %d) = %d, want %d", tt.score, tt.streak, got, tt.want)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	tests := []struct {
+		name        string
+		entries     []Entry
+		wantPoints  []int
+		wantStreaks []int
+		wantBest    int
+	}{
+		{
+			name:        "streak builds bonus",
+			entries:     []Entry{{Score: 7}, {Score: 8}, {Score: 10}},
+			wantPoints:  []int{70, 85, 110},
+			wantStreaks: []int{1, 2, 3},
+			wantBest:    3,
+		},
+		{
+			name:        "low score breaks streak",
+			entries:     []Entry{{Score: 9}, {Score: 9}, {Score: 6}, {Score: 9}},
+			wantPoints:  []int{90, 95, 60, 90},
+			wantStreaks: []int{1, 2, 0, 1},
+			wantBest:    2,
+		},
+		{
+			name: "bonus is capped",
+			entries: []Entry{
+				{Score: 10}, {Score: 10}, {Score: 10}, {Score: 10},
+				{Score: 10}, {Score: 10}, {Score: 10}, {Score: 10},
+			},
+			wantPoints:  []int{100, 105, 110, 115, 120, 125, 125, 125},
+			wantStreaks: []int{1, 2, 3, 4, 5, 6, 7, 8},
+			wantBest:    8,
+		},
+		{
+			name:        "repeat scores nothing and breaks streak",
+			entries:     []Entry{{Score: 9}, {Score: 9}, {Score: 10, Repeat: true}, {Score: 9}},
+			wantPoints:  []int{90, 95, 0, 90},
+			wantStreaks: []int{1, 2, 0, 1},
+			wantBest:    2,
+		},
+		{
+			name:        "injection scores nothing and breaks streak",
+			entries:     []Entry{{Score: 9}, {Score: 10, Injection: true}, {Score: 9}},
+			wantPoints:  []int{90, 0, 90},
+			wantStreaks: []int{1, 0, 1},
+			wantBest:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var j Journal
+			var points, streaks []int
+			total := 0
+			for _, e := range tt.entries {
+				got := j.Record(e)
+				points = append(points, got.Points)
+				streaks = append(streaks, got.Streak)
+				total += got.Points
+			}
+			if !slices.Equal(points, tt.wantPoints) {
+				t.Errorf("points = %v, want %v", points, tt.wantPoints)
+			}
+			if !slices.Equal(streaks, tt.wantStreaks) {
+				t.Errorf("streaks = %v, want %v", streaks, tt.wantStreaks)
+			}
+			if j.BestStreak != tt.wantBest {
+				t.Errorf("BestStreak = %d, want %d", j.BestStreak, tt.wantBest)
+			}
+			if j.TotalPoints != total {
+				t.Errorf("TotalPoints = %d, want %d", j.TotalPoints, total)
+			}
+			if j.Actions != len(tt.entries) {
+				t.Errorf("Actions = %d, want %d", j.Actions, len(tt.entries))
+			}
+		})
+	}
+}
+
+func TestRecordDropsOldestEntries(t *testing.T) {
+	var j Journal
+	for i := range maxEntries + 10 {
+		j.Record(Entry{Input: fmt.Sprint(i), Score: 5})
+	}
+	if len(j.Entries) != maxEntries {
+		t.Fatalf("len(Entries) = %d, want %d", len(j.Entries), maxEntries)
+	}
+	if j.Entries[0].Input != "10" {
+		t.Errorf("oldest entry = %q, want %q", j.Entries[0].Input, "10")
+	}
+	if j.Actions != maxEntries+10 {
+		t.Errorf("Actions = %d, want %d", j.Actions, maxEntries+10)
+	}
+}
+
+func TestInputsAndRecap(t *testing.T) {
+	j := Journal{Entries: []Entry{
+		{Input: "a", Outcome: "A"},
+		{Input: "b"},
+		{Input: "c", Outcome: "C"},
+	}}
+	tests := []struct {
+		n          int
+		wantInputs []string
+		wantRecap  string
+	}{
+		{n: 0, wantInputs: nil, wantRecap: ""},
+		{n: 1, wantInputs: []string{"c"}, wantRecap: "- C"},
+		{n: 2, wantInputs: []string{"b", "c"}, wantRecap: "- C"},
+		{n: 3, wantInputs: []string{"a", "b", "c"}, wantRecap: "- A\n- C"},
+		{n: 10, wantInputs: []string{"a", "b", "c"}, wantRecap: "- A\n- C"},
+	}
+	for _, tt := range tests {
+		if got := j.Inputs(tt.n); !slices.Equal(got, tt.wantInputs) {
+			t.Errorf("Inputs(%d) = %q, want %q", tt.n, got, tt.wantInputs)
+		}
+		if got := j.Recap(tt.n); got != tt.wantRecap {
+			t.Errorf("Recap(%d) = %q, want %q", tt.n, got, tt.wantRecap)
+		}
+	}
+
+	var empty Journal
+	if got := empty.Inputs(5); got != nil {
+		t.Errorf("empty Inputs(5) = %q, want nil", got)
+	}
+	if got := empty.Recap(5); got != "" {
+		t.Errorf("empty Recap(5) = %q, want empty", got)
+	}
+}
+
+func TestScene(t *testing.T) {
+	var j Journal
+	if got := j.Scene(); got != Opening {
+		t.Errorf("empty Scene() = %q, want the opening", got)
+	}
+	j.Entries = []Entry{{Outcome: "The door opens."}, {Input: "no outcome"}}
+	if got := j.Scene(); got != "The door opens." {
+		t.Errorf("Scene() = %q, want the last outcome", got)
+	}
+}
@@ -0,0 +1,110 @@
+package cefr
+
+// Level is a CEFR proficiency band, used as the game difficulty
+type Level string
+
+const (
+	A1 Level = "A1"
+	A2 Level = "A2"
+	B1 Level = "B1"
+	B2 Level = "B2"
+	C1 Level = "C1"
+	C2 Level = "C2"
+)
+
+// DefaultLevel is used when the config has no (or an unknown) difficulty
+const DefaultLevel = B1
+
+// Levels lists every band from easiest to hardest
+var Levels = []Level{A1, A2, B1, B2, C1, C2}
+
+// Profile describes how a band changes grading and combat balance
+type Profile struct {
+	Strictness   string  // How harshly the grader scores
+	Structures   string  // Grammar structures the grader expects
+	Vocabulary   string  // Vocabulary level for the DM narration
+	EnemyHPScale float64 // Multiplier applied to enemy max HP
+	CounterScale float64 // Multiplier applied to enemy counter-attack damage
+}
+
+var profiles = map[Level]Profile{
+	A1: {
+		Strictness:   "Be very lenient. Only punish mistakes that make the sentence hard to understand. Ignore punctuation and capitalization.",
+		Structures:   "simple present, 'to be', basic subject-verb-object sentences",
+		Vocabulary:   "very simple, everyday words and short sentences",
+		EnemyHPScale: 0.6,
+		CounterScale: 0.5,
+	},
+	A2: {
+		Strictness:   "Be lenient. Focus on verb forms and word order, forgive small spelling slips.",
+		Structures:   "simple past, present continuous, articles, basic prepositions",
+		Vocabulary:   "simple words and short sentences",
+		EnemyHPScale: 0.8,
+		CounterScale: 0.75,
+	},
+	B1: {
+		Strictness:   "Be fair. Punish grammar and spelling mistakes, reward clear and correct sentences.",
+		Structures:   "past and perfect tenses, comparatives, conjunctions, simple relative clauses",
+		Vocabulary:   "common vocabulary with some descriptive words",
+		EnemyHPScale: 1.0,
+		CounterScale: 1.0,
+	},
+	B2: {
+		Strictness:   "Be strict. Punish every grammar, spelling and punctuation mistake, and expect some variety.",
+		Structures:   "conditionals, passive voice, modal verbs, relative clauses",
+		Vocabulary:   "rich vocabulary with some idioms",
+		EnemyHPScale: 1.2,
+		CounterScale: 1.2,
+	},
+	C1: {
+		Strictness:   "Be very strict. Only give 9-10 to flawless sentences with varied structure and precise word choice.",
+		Structures:   "mixed conditionals, inversion, participle clauses, advanced tense usage",
+		Vocabulary:   "sophisticated vocabulary and varied sentence structure",
+		EnemyHPScale: 1.4,
+		CounterScale: 1.4,
+	},
+	C2: {
+		Strictness:   "Be merciless. Judge like a native-speaking editor: any awkward phrasing, unnatural collocation or stylistic flaw lowers the score.",
+		Structures:   "native-like idiomatic and stylistic control, subtle nuance and register",
+		Vocabulary:   "literary, archaic and rare vocabulary",
+		EnemyHPScale: 1.6,
+		CounterScale: 1.6,
+	},
+}
+
+// Parse converts a config string into a Level, falling back to DefaultLevel
+func Parse(s string) Level {
+	l := Level(s)
+	if _, ok := profiles[l]; ok {
+		return l
+	}
+	return DefaultLevel
+}
+
+// Profile returns the grading and balance profile of the level
+func (l Level) Profile() Profile {
+	if p, ok := profiles[l]; ok {
+		return p
+	}
+	return profiles[DefaultLevel]
+}
+
+// Next returns the following level, wrapping around after C2
+func (l Level) Next() Level {
+	for i, lvl := range Levels {
+		if lvl == l {
+			return Levels[(i+1)%len(Levels)]
+		}
+	}
+	return DefaultLevel
+}
+
+// Prev returns the preceding level, wrapping around before A1
+func (l Level) Prev() Level {
+	for i, lvl := range Levels {
+		if lvl == l {
+			return Levels[(i+len(Levels)-1)%len(Levels)]
+		}
+	}
+	return DefaultLevel
+}
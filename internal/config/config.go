@@ -13,9 +13,76 @@ type Config struct {
 	Provider     string `json:"provider"`
 	GeminiAPIKey string `json:"gemini_api_key"`
 	GeminiModel  string `json:"gemini_model"`
+	Difficulty   string `json:"difficulty"` // CEFR band, A1 through C2
+
+	AdaptiveDifficulty  bool   `json:"adaptive_difficulty"`
+	ExplanationLanguage string `json:"explanation_language"` // Learner's native language, empty to disable
+	Locale              string `json:"locale"`               // Interface language, e.g. "en" or "es"
+	TypingWarmup        bool   `json:"typing_warmup"`        // Retype a few phrases before the first fight
+	Persona             string `json:"persona"`              // DM persona ID, empty for the default
+	GenerateEnemies     bool   `json:"generate_enemies"`     // Mix LLM-generated enemies from the bestiary into runs
+	DisableNarration    bool   `json:"disable_narration"`    // Use the static scene texts instead of LLM narration
+
+	AntiFarm AntiFarm `json:"anti_farm"`
+
+	// Disk cache of LLM replies for identical requests
+	DisableCache  bool `json:"disable_cache"`
+	CacheTTLHours int  `json:"cache_ttl_hours"` // 0 uses the default of 7 days
+	CacheMaxMB    int  `json:"cache_max_mb"`    // 0 uses the default of 20 MB
+
+	// Token budget and pricing of paid providers
+	DailyTokenBudget  int     `json:"daily_token_budget"`  // 0 for no limit
+	GeminiInputPrice  float64 `json:"gemini_input_price"`  // USD per million prompt tokens, 0 uses the default
+	GeminiOutputPrice float64 `json:"gemini_output_price"` // USD per million completion tokens, 0 uses the default
+
+	// Retries and rate limiting of LLM calls
+	MaxRetries        int `json:"max_retries"`         // 0 uses the default of 3
+	RequestsPerMinute int `json:"requests_per_minute"` // 0 for no limit
+
+	// LLM middleware: debug logging and tracing
+	LogPrompts   bool `json:"log_prompts"`   // Write prompts to debug.log, with secrets redacted
+	LogResponses bool `json:"log_responses"` // Write raw model replies to debug.log
+	Tracing      bool `json:"tracing"`       // Emit OpenTelemetry spans for LLM calls
+}
+
+// DailyTokenBudgets are the daily budgets offered in Settings, 0 is no limit
+var DailyTokenBudgets = []int{0, 50_000, 100_000, 250_000, 500_000, 1_000_000}
+
+// Default Gemini prices in USD per million tokens
+const (
+	DefaultGeminiInputPrice  = 0.50
+	DefaultGeminiOutputPrice = 3.00
+)
+
+// GeminiPrices returns the configured Gemini prices, or the defaults
+func (c *Config) GeminiPrices() (input, output float64) {
+	input, output = c.GeminiInputPrice, c.GeminiOutputPrice
+	if input <= 0 {
+		input = DefaultGeminiInputPrice
+	}
+	if output <= 0 {
+		output = DefaultGeminiOutputPrice
+	}
+	return input, output
 }
 
+// AntiFarm holds the thresholds for repeated sentences in combat. Zero
+// values fall back to the game defaults.
+type AntiFarm struct {
+	Similarity   float64 `json:"similarity"`    // Edit distance similarity (0-1) that counts as a repeat
+	NGramOverlap float64 `json:"ngram_overlap"` // Shared word bigrams (0-1) that count as a repeat
+	Decay        float64 `json:"decay"`         // Damage multiplier applied per repeat
+}
+
+// ExplanationLanguages are the native languages offered in Settings
+var ExplanationLanguages = []string{"", "Spanish", "Portuguese", "French", "German", "Italian", "Russian", "Japanese", "Chinese", "Korean", "Arabic"}
+
 func GetConfigPath() (string, error) {
+	return GetDataPath(configFileName)
+}
+
+// GetDataPath returns the path of a file stored next to the config file
+func GetDataPath(name string) (string, error) {
 	// Try to use UserConfigDir first
 	configDir, err := os.UserConfigDir()
 	if err == nil {
@@ -24,11 +91,11 @@ func GetConfigPath() (string, error) {
 		if _, err := os.Stat(appDir); os.IsNotExist(err) {
 			os.MkdirAll(appDir, 0755)
 		}
-		return filepath.Join(appDir, configFileName), nil
+		return filepath.Join(appDir, name), nil
 	}
 
 	// Fallback to current directory
-	return configFileName, nil
+	return name, nil
 }
 
 func LoadConfig() (*Config, error) {
@@ -0,0 +1,326 @@
+package eval
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/erwaen/type-glish/internal/cefr"
+	"github.com/erwaen/type-glish/internal/llm"
+	"github.com/erwaen/type-glish/internal/textsim"
+)
+
+// ReportFile is the comparison report rewritten after every evaluation
+const ReportFile = "eval-report.md"
+
+//go:embed corpus.json
+var corpusJSON []byte
+
+// Item is a labelled sentence of the corpus
+type Item struct {
+	ID        string `json:"id"`
+	Sentence  string `json:"sentence"`
+	Reference string `json:"reference"` // Expected correction
+	MinScore  int    `json:"min_score"` // Expected score range at the default level
+	MaxScore  int    `json:"max_score"`
+	Enemy     string `json:"enemy"`
+	Location  string `json:"location"`
+	Anchor    bool   `json:"anchor"` // Used by the quick calibration
+}
+
+// Expected returns the middle of the expected score range
+func (i Item) Expected() float64 {
+	return float64(i.MinScore+i.MaxScore) / 2
+}
+
+// LoadCorpus returns the bundled corpus
+func LoadCorpus() ([]Item, error) {
+	var items []Item
+	if err := json.Unmarshal(corpusJSON, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse eval corpus: %w", err)
+	}
+	return items, nil
+}
+
+// Anchors returns the corpus items used by the quick calibration
+func Anchors(items []Item) []Item {
+	var anchors []Item
+	for _, i := range items {
+		if i.Anchor {
+			anchors = append(anchors, i)
+		}
+	}
+	return anchors
+}
+
+// ItemResult holds every graded call for one corpus item
+type ItemResult struct {
+	ID        string   `json:"id"`
+	Scores    []int    `json:"scores"`     // Scores of the valid replies, after calibration
+	RawScores []int    `json:"raw_scores"` // The same scores before calibration
+	InRange   int      `json:"in_range"`
+	Invalid   int      `json:"invalid"` // Replies that were not valid JSON
+	Errors    int      `json:"errors"`  // Calls that failed before a reply
+	Examples  []string `json:"examples,omitempty"`
+}
+
+// Result is the evaluation of one provider and model over the corpus
+type Result struct {
+	Model      string     `json:"model"`
+	Date       time.Time  `json:"date"`
+	Level      cefr.Level `json:"level"`
+	Repeats    int        `json:"repeats"`
+	Calibrated bool       `json:"calibrated"` // Scores went through a stored calibration
+	Prompt     string     `json:"prompt"`     // Combat prompt template tag, e.g. "combat@2"
+
+	Calls        int     `json:"calls"`
+	Errors       int     `json:"errors"`        // Calls that failed before a reply
+	JSONValidity float64 `json:"json_validity"` // Share of replies that were valid JSON
+	Agreement    float64 `json:"agreement"`     // Share of valid replies scored in the expected range
+
+	CorrectionMatch      float64 `json:"correction_match"`      // Share of corrections equal to the reference
+	CorrectionSimilarity float64 `json:"correction_similarity"` // Mean similarity to the reference
+	ScoreStdDev          float64 `json:"score_stddev"`          // Mean spread of scores across repeated calls
+
+	MeanLatencyMS float64 `json:"mean_latency_ms"`
+	P95LatencyMS  float64 `json:"p95_latency_ms"`
+
+	Items []ItemResult `json:"items"`
+}
+
+// Run grades every corpus item repeats times and computes the metrics.
+// progress is called after each call and may be nil.
+func Run(client *llm.Client, items []Item, repeats int, level cefr.Level, progress func(done, total int)) Result {
+	res := Result{
+		Model:      client.ProviderName(),
+		Date:       time.Now(),
+		Level:      level,
+		Repeats:    repeats,
+		Calibrated: !client.Calibration().IsZero(),
+		Prompt:     llm.GetPrompt(llm.CombatPromptName).Tag(),
+	}
+
+	var latencies []float64
+	var valid, inRange, matches int
+	var similarity, spread float64
+	var spreadItems int
+
+	for _, item := range items {
+		ir := ItemResult{ID: item.ID}
+		for range repeats {
+			start := time.Now()
+			msg := client.AnalyzeCombatAction(llm.CombatRequest{
+				Action:      item.Sentence,
+				Enemy:       item.Enemy,
+				Location:    item.Location,
+				Level:       level,
+				PlayerHP:    100,
+				PlayerMaxHP: 100,
+				EnemyHP:     50,
+				EnemyMaxHP:  50,
+			}).(llm.CombatAssessmentMsg)
+			latencies = append(latencies, float64(time.Since(start).Milliseconds()))
+			res.Calls++
+
+			if progress != nil {
+				progress(res.Calls, len(items)*repeats)
+			}
+
+			if msg.Err != nil {
+				if errors.Is(msg.Err, llm.ErrInvalidJSON) {
+					ir.Invalid++
+				} else {
+					ir.Errors++
+					res.Errors++
+				}
+				continue
+			}
+
+			valid++
+			a := msg.Data
+			ir.Scores = append(ir.Scores, a.GrammarScore)
+			ir.RawScores = append(ir.RawScores, a.RawScore)
+			if a.GrammarScore >= item.MinScore && a.GrammarScore <= item.MaxScore {
+				ir.InRange++
+				inRange++
+			}
+
+			got, want := textsim.Normalize(a.CorrectedSentence), textsim.Normalize(item.Reference)
+			if got == want {
+				matches++
+			} else if len(ir.Examples) < 2 && !slices.Contains(ir.Examples, a.CorrectedSentence) {
+				ir.Examples = append(ir.Examples, a.CorrectedSentence)
+			}
+			similarity += textsim.Similarity(got, want)
+		}
+
+		if len(ir.Scores) > 1 {
+			spread += stdDev(ir.Scores)
+			spreadItems++
+		}
+		res.Items = append(res.Items, ir)
+	}
+
+	if replies := res.Calls - res.Errors; replies > 0 {
+		res.JSONValidity = float64(valid) / float64(replies)
+	}
+	if valid > 0 {
+		res.Agreement = float64(inRange) / float64(valid)
+		res.CorrectionMatch = float64(matches) / float64(valid)
+		res.CorrectionSimilarity = similarity / float64(valid)
+	}
+	if spreadItems > 0 {
+		res.ScoreStdDev = spread / float64(spreadItems)
+	}
+	if len(latencies) > 0 {
+		slices.Sort(latencies)
+		var sum float64
+		for _, l := range latencies {
+			sum += l
+		}
+		res.MeanLatencyMS = sum / float64(len(latencies))
+		res.P95LatencyMS = latencies[int(math.Ceil(0.95*float64(len(latencies))))-1]
+	}
+	return res
+}
+
+// Calibration fits the raw scores of the result to the expected scores of
+// the corpus items
+func (r Result) Calibration(items []Item) (llm.Calibration, error) {
+	byID := make(map[string]Item, len(items))
+	for _, i := range items {
+		byID[i.ID] = i
+	}
+
+	var raw, expected []float64
+	for _, ir := range r.Items {
+		item, ok := byID[ir.ID]
+		if !ok || len(ir.RawScores) == 0 {
+			continue
+		}
+		var sum float64
+		for _, s := range ir.RawScores {
+			sum += float64(s)
+		}
+		raw = append(raw, sum/float64(len(ir.RawScores)))
+		expected = append(expected, item.Expected())
+	}
+	return llm.FitCalibration(r.Model, raw, expected)
+}
+
+func stdDev(values []int) float64 {
+	var mean float64
+	for _, v := range values {
+		mean += float64(v)
+	}
+	mean /= float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (float64(v) - mean) * (float64(v) - mean)
+	}
+	return math.Sqrt(variance / float64(len(values)))
+}
+
+// fileName returns the result file of a model, e.g.
+// "eval-llamacpp-qwen2.5-3b-instruct-q4_k_m.gguf.json"
+func fileName(model string) string {
+	slug := strings.Map(func(r rune) rune {
+		if r == '/' || r == ' ' || r == ':' || r == '\\' {
+			return '-'
+		}
+		return r
+	}, model)
+	return "eval-" + slug + ".json"
+}
+
+// Save writes the result to its per-model file in dir
+func (r Result) Save(dir string) (string, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fileName(r.Model))
+	return path, os.WriteFile(path, data, 0644)
+}
+
+// LoadResults reads every per-model result in dir, best agreement first
+func LoadResults(dir string) ([]Result, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "eval-*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		var r Result
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", p, err)
+		}
+		results = append(results, r)
+	}
+
+	slices.SortFunc(results, func(a, b Result) int {
+		if a.Agreement != b.Agreement {
+			if a.Agreement > b.Agreement {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Model, b.Model)
+	})
+	return results, nil
+}
+
+// WriteReport writes a Markdown report comparing the results
+func WriteReport(dir string, results []Result) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("# type-glish model evaluation\n\n")
+	sb.WriteString("Agreement is the share of valid replies scored inside the expected range. ")
+	sb.WriteString("Score spread is the mean standard deviation of repeated calls on the same sentence.\n\n")
+
+	sb.WriteString("| Model | Agreement | JSON valid | Correction match | Correction similarity | Score spread | Mean latency | p95 latency | Errors | Date |\n")
+	sb.WriteString("|---|---|---|---|---|---|---|---|---|---|\n")
+	for _, r := range results {
+		fmt.Fprintf(&sb, "| %s | %.0f%% | %.0f%% | %.0f%% | %.2f | %.2f | %.0f ms | %.0f ms | %d/%d | %s |\n",
+			r.Model, r.Agreement*100, r.JSONValidity*100, r.CorrectionMatch*100, r.CorrectionSimilarity,
+			r.ScoreStdDev, r.MeanLatencyMS, r.P95LatencyMS, r.Errors, r.Calls, r.Date.Format("2006-01-02"))
+	}
+
+	for _, r := range results {
+		calibrated := "raw scores"
+		if r.Calibrated {
+			calibrated = "calibrated scores"
+		}
+		prompt := r.Prompt
+		if prompt == "" {
+			prompt = "unknown prompt"
+		}
+		fmt.Fprintf(&sb, "\n## %s\n\nLevel %s, %d calls per sentence, %s, %s.\n\n", r.Model, r.Level, r.Repeats, calibrated, prompt)
+		sb.WriteString("| Sentence | Scores | In range | Invalid JSON | Other corrections |\n")
+		sb.WriteString("|---|---|---|---|---|\n")
+		for _, ir := range r.Items {
+			scores := make([]string, len(ir.Scores))
+			for i, s := range ir.Scores {
+				scores[i] = fmt.Sprint(s)
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %d/%d | %d | %s |\n",
+				ir.ID, strings.Join(scores, ", "), ir.InRange, len(ir.Scores), ir.Invalid,
+				strings.ReplaceAll(strings.Join(ir.Examples, " / "), "|", "\\|"))
+		}
+	}
+
+	path := filepath.Join(dir, ReportFile)
+	return path, os.WriteFile(path, []byte(sb.String()), 0644)
+}
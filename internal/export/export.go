@@ -0,0 +1,139 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/erwaen/type-glish/internal/review"
+	"github.com/erwaen/type-glish/internal/vocab"
+)
+
+// Output file names
+const (
+	CorrectionsCSV = "type-glish-corrections.csv"
+	VocabularyCSV  = "type-glish-vocabulary.csv"
+	AnkiTSV        = "type-glish-anki.txt"
+)
+
+// Formats accepted by Write
+const (
+	FormatCSV  = "csv"
+	FormatAnki = "anki"
+	FormatAll  = "all"
+)
+
+// WriteCorrectionsCSV writes the collected corrections as CSV with a header row
+func WriteCorrectionsCSV(w io.Writer, cards []*review.Card) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"original", "corrected", "category", "explanation", "native_explanation", "created"})
+	for _, c := range cards {
+		cw.Write([]string{c.Original, c.Corrected, c.Category, c.Comment, c.Explanation, c.Created.Format(time.RFC3339)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteVocabularyCSV writes the collected vocabulary as CSV with a header row
+func WriteVocabularyCSV(w io.Writer, entries []vocab.Entry) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"word", "sentence", "added"})
+	for _, e := range entries {
+		cw.Write([]string{e.Word, e.Sentence, e.Added.Format(time.RFC3339)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteAnki writes corrections and vocabulary as Anki-importable
+// tab-separated notes (front, back, tags)
+func WriteAnki(w io.Writer, cards []*review.Card, entries []vocab.Entry) error {
+	// Anki reads these header lines to configure the import
+	header := "#separator:tab\n#html:true\n#columns:Front\tBack\tTags\n#tags column:3\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	for _, c := range cards {
+		back := ankiField(c.Corrected)
+		if c.Comment != "" {
+			back += "<br><br><i>" + ankiField(c.Comment) + "</i>"
+		}
+		if c.Explanation != "" {
+			back += "<br><br>" + ankiField(c.Explanation)
+		}
+		tags := "type-glish correction"
+		if c.Category != "" && c.Category != "none" {
+			tags += " category::" + ankiTag(c.Category)
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", ankiField(c.Original), back, tags); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range entries {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", ankiField(e.Word), ankiField(e.Sentence), "type-glish vocabulary"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write exports corrections and vocabulary into dir in the given format
+// and returns the paths of the files written
+func Write(dir, format string, cards []*review.Card, entries []vocab.Entry) ([]string, error) {
+	if format != FormatCSV && format != FormatAnki && format != FormatAll {
+		return nil, fmt.Errorf("unknown export format %q (want csv, anki or all)", format)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	var written []string
+	writeFile := func(name string, write func(io.Writer) error) error {
+		path := filepath.Join(dir, name)
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := write(f); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		written = append(written, path)
+		return nil
+	}
+
+	if format == FormatCSV || format == FormatAll {
+		if err := writeFile(CorrectionsCSV, func(w io.Writer) error { return WriteCorrectionsCSV(w, cards) }); err != nil {
+			return written, err
+		}
+		if err := writeFile(VocabularyCSV, func(w io.Writer) error { return WriteVocabularyCSV(w, entries) }); err != nil {
+			return written, err
+		}
+	}
+	if format == FormatAnki || format == FormatAll {
+		if err := writeFile(AnkiTSV, func(w io.Writer) error { return WriteAnki(w, cards, entries) }); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// ankiField makes text safe for a tab-separated HTML field
+func ankiField(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\t", " ", "\n", "<br>", "\r", "")
+	return r.Replace(s)
+}
+
+// ankiTag turns a category into a single Anki tag
+func ankiTag(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), "_")
+}
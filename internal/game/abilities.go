@@ -0,0 +1,246 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/erwaen/type-glish/internal/llm"
+)
+
+// Status effect kinds
+const (
+	StatusStunned  = "stunned"  // Player: the next attack deals half damage
+	StatusPoisoned = "poisoned" // Player: loses Power HP at the end of each turn
+	StatusShielded = "shielded" // Enemy: the next attack loses up to Power damage
+)
+
+// StatusEffect is a status on the player or on the enemy
+type StatusEffect struct {
+	Kind  string
+	Turns int // Poison turns left; stuns and shields last until the next attack
+	Power int // Poison damage per turn, or damage a shield absorbs
+}
+
+// Effects are the statuses of one side of the fight
+type Effects []StatusEffect
+
+// Get returns the status of the given kind, or nil
+func (e Effects) Get(kind string) *StatusEffect {
+	for i := range e {
+		if e[i].Kind == kind {
+			return &e[i]
+		}
+	}
+	return nil
+}
+
+// Add applies a status. A status of the same kind is refreshed, keeping
+// the stronger power and the longer duration.
+func (e *Effects) Add(s StatusEffect) {
+	if cur := e.Get(s.Kind); cur != nil {
+		cur.Power = max(cur.Power, s.Power)
+		cur.Turns = max(cur.Turns, s.Turns)
+		return
+	}
+	*e = append(*e, s)
+}
+
+// Remove drops the status of the given kind
+func (e *Effects) Remove(kind string) {
+	kept := (*e)[:0]
+	for _, s := range *e {
+		if s.Kind != kind {
+			kept = append(kept, s)
+		}
+	}
+	*e = kept
+}
+
+// Ability is an enemy's special attack. The model only narrates the fight;
+// whether an ability fires and what it does is decided here.
+type Ability struct {
+	Name        string
+	Description string // Shown in the combat view
+	Narration   string // Shown when it fires, %s is the enemy name
+
+	BelowScore      int  // Fires when the grammar score is below this, 0 for any score
+	RepeatedMistake bool // Fires only when the player repeats the error category of the last turn
+
+	Inflict     StatusEffect // Status put on the player, zero Kind for none
+	Grant       StatusEffect // Status the enemy gains, zero Kind for none
+	BonusDamage int          // Extra counter-attack damage
+}
+
+// abilities are the signature attacks of the predefined enemies
+var abilities = map[string]Ability{
+	"Goblin": {
+		Name:        "Rusty Dagger",
+		Description: "poisons you when your score is below 5",
+		Narration:   "The %s slips its rusty dagger past your guard. The wound burns with poison.",
+		BelowScore:  5,
+		Inflict:     StatusEffect{Kind: StatusPoisoned, Turns: 3, Power: 2},
+	},
+	"Syntax Spider": {
+		Name:        "Sticky Web",
+		Description: "halves your next damage when your score is below 7",
+		Narration:   "The %s wraps you in a web of tangled clauses. Your next strike will land at half strength.",
+		BelowScore:  7,
+		Inflict:     StatusEffect{Kind: StatusStunned},
+	},
+	"Skeleton": {
+		Name:        "Bone Wall",
+		Description: "raises a shield that absorbs 6 damage when your score is below 7",
+		Narration:   "The %s raises a wall of rattling bones between you.",
+		BelowScore:  7,
+		Grant:       StatusEffect{Kind: StatusShielded, Power: 6},
+	},
+	"Dark Wizard": {
+		Name:        "Curse of Tenses",
+		Description: "curses you with poison when your score is below 6",
+		Narration:   "The %s mutters a curse in the wrong tense. A sickly light seeps into you.",
+		BelowScore:  6,
+		Inflict:     StatusEffect{Kind: StatusPoisoned, Turns: 2, Power: 3},
+	},
+	"Troll": {
+		Name:            "Taunt",
+		Description:     "hits much harder when you repeat your last mistake",
+		Narration:       "The %s roars with laughter: \"Same mistake again!\" His club lands twice as hard.",
+		RepeatedMistake: true,
+		BonusDamage:     6,
+	},
+	"Grammar Golem": {
+		Name:        "Dictionary Wall",
+		Description: "raises a shield that absorbs 8 damage when your score is below 8",
+		Narration:   "The %s stacks a wall of heavy dictionaries in front of itself.",
+		BelowScore:  8,
+		Grant:       StatusEffect{Kind: StatusShielded, Power: 8},
+	},
+}
+
+// tierAbilities are used by enemies without their own ability, like the
+// generated ones
+var tierAbilities = map[int]Ability{
+	1: {
+		Name:        "Venomous Bite",
+		Description: "poisons you when your score is below 5",
+		Narration:   "The %s sinks its fangs into you. Poison spreads through the wound.",
+		BelowScore:  5,
+		Inflict:     StatusEffect{Kind: StatusPoisoned, Turns: 2, Power: 2},
+	},
+	2: {
+		Name:        "Dazing Blow",
+		Description: "halves your next damage when your score is below 6",
+		Narration:   "The %s lands a dazing blow. Your next strike will land at half strength.",
+		BelowScore:  6,
+		Inflict:     StatusEffect{Kind: StatusStunned},
+	},
+	3: {
+		Name:        "Iron Guard",
+		Description: "raises a shield that absorbs 6 damage when your score is below 7",
+		Narration:   "The %s braces behind an iron guard.",
+		BelowScore:  7,
+		Grant:       StatusEffect{Kind: StatusShielded, Power: 6},
+	},
+}
+
+// AbilityFor returns the special attack of an enemy, or nil if it has none
+func AbilityFor(e *Enemy) *Ability {
+	if e == nil || e.Name == MemoryWraith.Name {
+		return nil
+	}
+	if a, ok := abilities[e.Name]; ok {
+		return &a
+	}
+	if a, ok := tierAbilities[e.Tier]; ok {
+		return &a
+	}
+	return nil
+}
+
+// fires reports whether the ability triggers on a turn
+func (ab *Ability) fires(score int, repeatedMistake bool) bool {
+	if ab.RepeatedMistake && !repeatedMistake {
+		return false
+	}
+	return ab.BelowScore == 0 || score < ab.BelowScore
+}
+
+// TurnEffects is what abilities and statuses did during a combat turn,
+// for the result screen
+type TurnEffects struct {
+	Ability   string // Name of the ability that fired, empty if none
+	Narration string
+	Halved    int // Damage lost to a stun
+	Absorbed  int // Damage absorbed by the enemy's shield
+	Bonus     int // Extra counter damage from the ability
+	Poison    int // HP lost to poison
+}
+
+// Any reports whether anything happened
+func (t TurnEffects) Any() bool {
+	return t.Ability != "" || t.Halved > 0 || t.Absorbed > 0 || t.Poison > 0
+}
+
+// ClearEffects removes every status, at the start of a fight
+func (c *Context) ClearEffects() {
+	c.PlayerEffects = nil
+	c.EnemyEffects = nil
+	c.LastEffects = TurnEffects{}
+}
+
+// repeatedMistake reports whether the player made the same kind of mistake
+// as in the previous turn of the fight
+func (c *Context) repeatedMistake(category string) bool {
+	if category == "" || category == "none" || len(c.CombatHistory) == 0 {
+		return false
+	}
+	return c.CombatHistory[len(c.CombatHistory)-1].ErrorCategory == category
+}
+
+// resolveEffects applies the statuses and the enemy's ability to a
+// validated assessment: a stun halves the player's damage, a shield
+// absorbs part of it, poison ticks, and then the ability may fire. The
+// enemy only uses its ability if it survives the attack.
+func (c *Context) resolveEffects(a *llm.CombatAssessment) TurnEffects {
+	var fx TurnEffects
+
+	if c.PlayerEffects.Get(StatusStunned) != nil {
+		fx.Halved = a.DamageDealt - a.DamageDealt/2
+		a.DamageDealt -= fx.Halved
+		c.PlayerEffects.Remove(StatusStunned)
+	}
+	if shield := c.EnemyEffects.Get(StatusShielded); shield != nil && a.DamageDealt > 0 {
+		fx.Absorbed = min(shield.Power, a.DamageDealt)
+		a.DamageDealt -= fx.Absorbed
+		c.EnemyEffects.Remove(StatusShielded)
+	}
+
+	if poison := c.PlayerEffects.Get(StatusPoisoned); poison != nil {
+		fx.Poison = poison.Power
+		poison.Turns--
+		if poison.Turns <= 0 {
+			c.PlayerEffects.Remove(StatusPoisoned)
+		}
+	}
+
+	ab := AbilityFor(c.CurrentEnemy)
+	if ab == nil || c.CurrentEnemy.HP-a.DamageDealt <= 0 {
+		return fx
+	}
+	if !ab.fires(a.GrammarScore, c.repeatedMistake(a.ErrorCategory)) {
+		return fx
+	}
+
+	fx.Ability = ab.Name
+	fx.Narration = fmt.Sprintf(ab.Narration, c.CurrentEnemy.Name)
+	if ab.Inflict.Kind != "" {
+		c.PlayerEffects.Add(ab.Inflict)
+	}
+	if ab.Grant.Kind != "" {
+		c.EnemyEffects.Add(ab.Grant)
+	}
+	if ab.BonusDamage > 0 {
+		fx.Bonus = ScaleCounterDamage(ab.BonusDamage, c.Level())
+		a.DamageReceived += fx.Bonus
+	}
+	return fx
+}
@@ -0,0 +1,258 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/erwaen/type-glish/internal/cefr"
+	"github.com/erwaen/type-glish/internal/config"
+	"github.com/erwaen/type-glish/internal/llm"
+	"github.com/erwaen/type-glish/internal/runlog"
+)
+
+// newFight returns a context in a fight against the named enemy at B1,
+// where counter damage is not scaled
+func newFight(enemy string, hp int) *Context {
+	return &Context{
+		Stats:        PlayerStats{HP: 100, Level: 1},
+		Difficulty:   cefr.B1,
+		AntiFarm:     NewAntiFarm(config.AntiFarm{}),
+		CurrentEnemy: &Enemy{Name: enemy, HP: hp, MaxHP: hp, Tier: 1},
+		Run:          &runlog.Run{},
+	}
+}
+
+// hit is a relevant combat assessment within the game rules
+func hit(score, dealt, received int, category string) *llm.CombatAssessment {
+	return &llm.CombatAssessment{
+		GrammarScore:   score,
+		DamageDealt:    dealt,
+		DamageReceived: received,
+		IsRelevant:     true,
+		ErrorCategory:  category,
+	}
+}
+
+func TestEffectsAdd(t *testing.T) {
+	var e Effects
+	e.Add(StatusEffect{Kind: StatusPoisoned, Turns: 3, Power: 2})
+	e.Add(StatusEffect{Kind: StatusShielded, Power: 6})
+	e.Add(StatusEffect{Kind: StatusPoisoned, Turns: 2, Power: 3})
+
+	if len(e) != 2 {
+		t.Fatalf("Effects = %+v, want one status per kind", e)
+	}
+	if p := e.Get(StatusPoisoned); p == nil || p.Turns != 3 || p.Power != 3 {
+		t.Errorf("refreshed poison = %+v, want the longer duration and the stronger power", p)
+	}
+
+	e.Remove(StatusPoisoned)
+	if e.Get(StatusPoisoned) != nil || e.Get(StatusShielded) == nil {
+		t.Errorf("after Remove = %+v, want only the shield", e)
+	}
+	e.Remove(StatusStunned)
+	if len(e) != 1 {
+		t.Errorf("removing a missing status changed %+v", e)
+	}
+}
+
+func TestAbilityFor(t *testing.T) {
+	tests := []struct {
+		name  string
+		enemy *Enemy
+		want  string
+	}{
+		{"no enemy", nil, ""},
+		{"predefined enemy", &Enemy{Name: "Goblin", Tier: 1}, "Rusty Dagger"},
+		{"predefined ability wins over the tier", &Enemy{Name: "Troll", Tier: 1}, "Taunt"},
+		{"generated enemy uses its tier", &Enemy{Name: "Comma Imp", Tier: 2}, "Dazing Blow"},
+		{"tier without an ability", &Enemy{Name: "Comma Imp", Tier: 4}, ""},
+		{"Memory Wraith", &Enemy{Name: MemoryWraith.Name, Tier: 1}, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if ab := AbilityFor(tt.enemy); ab != nil {
+			got = ab.Name
+		}
+		if got != tt.want {
+			t.Errorf("%s: AbilityFor = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAbilityFires(t *testing.T) {
+	below := Ability{BelowScore: 5}
+	always := Ability{}
+	repeat := Ability{RepeatedMistake: true}
+
+	tests := []struct {
+		name     string
+		ability  Ability
+		score    int
+		repeated bool
+		want     bool
+	}{
+		{"below the threshold", below, 4, false, true},
+		{"at the threshold", below, 5, false, false},
+		{"no threshold", always, 10, false, true},
+		{"repeated mistake", repeat, 9, true, true},
+		{"new mistake", repeat, 1, false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.ability.fires(tt.score, tt.repeated); got != tt.want {
+			t.Errorf("%s: fires = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPoisonDuration(t *testing.T) {
+	c := newFight("Goblin", 200)
+
+	// Rusty Dagger poisons for 3 turns at 2 HP, starting next turn
+	c.ApplyCombatAssessment(hit(4, 6, 11, "spelling"))
+	if c.LastEffects.Ability != "Rusty Dagger" || c.LastEffects.Poison != 0 {
+		t.Fatalf("LastEffects = %+v, want the ability without a tick", c.LastEffects)
+	}
+	if c.Stats.HP != 89 {
+		t.Fatalf("HP = %d, want 89", c.Stats.HP)
+	}
+
+	// Good turns let the poison run out
+	for turn, wantHP := range []int{84, 79, 74, 71} {
+		c.ApplyCombatAssessment(hit(8, 12, 3, "none"))
+		wantPoison := 2
+		if turn == 3 {
+			wantPoison = 0
+		}
+		if c.LastEffects.Poison != wantPoison {
+			t.Errorf("turn %d: Poison = %d, want %d", turn+2, c.LastEffects.Poison, wantPoison)
+		}
+		if c.Stats.HP != wantHP {
+			t.Errorf("turn %d: HP = %d, want %d", turn+2, c.Stats.HP, wantHP)
+		}
+	}
+	if c.PlayerEffects.Get(StatusPoisoned) != nil {
+		t.Errorf("PlayerEffects = %+v, want the poison gone", c.PlayerEffects)
+	}
+}
+
+func TestPoisonRefresh(t *testing.T) {
+	c := newFight("Goblin", 200)
+	c.ApplyCombatAssessment(hit(4, 6, 11, "spelling"))
+	c.ApplyCombatAssessment(hit(3, 4, 11, "spelling"))
+
+	// The second poison ticked once and then refreshed the duration
+	p := c.PlayerEffects.Get(StatusPoisoned)
+	if p == nil || p.Turns != 3 || p.Power != 2 || len(c.PlayerEffects) != 1 {
+		t.Errorf("PlayerEffects = %+v, want one poison refreshed to 3 turns", c.PlayerEffects)
+	}
+}
+
+func TestStunHalvesNextAttack(t *testing.T) {
+	c := newFight("Syntax Spider", 200)
+	c.ApplyCombatAssessment(hit(6, 9, 6, "articles"))
+	if c.PlayerEffects.Get(StatusStunned) == nil {
+		t.Fatalf("PlayerEffects = %+v, want stunned", c.PlayerEffects)
+	}
+
+	c.ApplyCombatAssessment(hit(9, 13, 3, "none"))
+	if c.LastEffects.Halved != 7 || c.LastStrike.Multiplier != 1 {
+		t.Errorf("LastEffects = %+v, LastStrike = %+v, want 7 damage lost to the stun", c.LastEffects, c.LastStrike)
+	}
+	if c.CurrentEnemy.HP != 200-9-6 {
+		t.Errorf("enemy HP = %d, want %d", c.CurrentEnemy.HP, 200-9-6)
+	}
+	if c.PlayerEffects.Get(StatusStunned) != nil {
+		t.Error("stun lasted past the next attack")
+	}
+}
+
+func TestShieldAbsorbsOnce(t *testing.T) {
+	tests := []struct {
+		name         string
+		dealt        int
+		wantAbsorbed int
+		wantDealt    int
+	}{
+		{"hit stronger than the shield", 12, 6, 6},
+		{"hit weaker than the shield", 4, 4, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFight("Skeleton", 200)
+			c.ApplyCombatAssessment(hit(6, 9, 6, "spelling"))
+			if c.EnemyEffects.Get(StatusShielded) == nil {
+				t.Fatalf("EnemyEffects = %+v, want shielded", c.EnemyEffects)
+			}
+
+			a := hit(8, tt.dealt, 3, "none")
+			c.ApplyCombatAssessment(a)
+			if c.LastEffects.Absorbed != tt.wantAbsorbed || a.DamageDealt != tt.wantDealt {
+				t.Errorf("absorbed %d, dealt %d, want %d and %d", c.LastEffects.Absorbed, a.DamageDealt, tt.wantAbsorbed, tt.wantDealt)
+			}
+			if c.EnemyEffects.Get(StatusShielded) != nil {
+				t.Error("shield lasted past the next attack")
+			}
+		})
+	}
+}
+
+func TestTauntOnRepeatedMistake(t *testing.T) {
+	tests := []struct {
+		name      string
+		category  string
+		wantBonus int
+	}{
+		{"same mistake", "verb tense", 6},
+		{"other mistake", "articles", 0},
+		{"no mistake", "none", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFight("Troll", 200)
+			c.ApplyCombatAssessment(hit(6, 9, 6, "verb tense"))
+
+			a := hit(6, 9, 6, tt.category)
+			c.ApplyCombatAssessment(a)
+			if c.LastEffects.Bonus != tt.wantBonus || a.DamageReceived != 6+tt.wantBonus {
+				t.Errorf("bonus %d, received %d, want %d and %d", c.LastEffects.Bonus, a.DamageReceived, tt.wantBonus, 6+tt.wantBonus)
+			}
+		})
+	}
+}
+
+func TestAbilitySkippedWhenEnemyDies(t *testing.T) {
+	c := newFight("Goblin", 5)
+	c.ApplyCombatAssessment(hit(4, 6, 11, "spelling"))
+	if c.CurrentEnemy.HP != 0 || c.LastEffects.Ability != "" || len(c.PlayerEffects) != 0 {
+		t.Errorf("enemy HP %d, LastEffects %+v, PlayerEffects %+v, want a dead enemy and no ability", c.CurrentEnemy.HP, c.LastEffects, c.PlayerEffects)
+	}
+}
+
+func TestEffectsAfterAntiFarm(t *testing.T) {
+	const sentence = "I attack the spider with my sword."
+	c := newFight("Syntax Spider", 200)
+	c.PlayerEffects.Add(StatusEffect{Kind: StatusStunned})
+	c.Run.Turns = []runlog.Turn{{Kind: runlog.KindCombat, Input: sentence}}
+	c.LastInput = sentence
+
+	// The repeat halves the damage first, then the stun halves what is left
+	a := hit(9, 12, 3, "none")
+	c.ApplyCombatAssessment(a)
+	if a.Repeats != 1 || c.LastEffects.Halved != 3 || a.DamageDealt != 3 {
+		t.Errorf("repeats %d, halved %d, dealt %d, want 1, 3 and 3", a.Repeats, c.LastEffects.Halved, a.DamageDealt)
+	}
+	if c.LastStrike.Combo != 0 {
+		t.Errorf("Combo = %d, want a repeat to build no combo", c.LastStrike.Combo)
+	}
+}
+
+func TestClearEffects(t *testing.T) {
+	c := newFight("Goblin", 200)
+	c.ApplyCombatAssessment(hit(4, 6, 11, "spelling"))
+	c.EnemyEffects.Add(StatusEffect{Kind: StatusShielded, Power: 6})
+
+	c.ClearEffects()
+	if len(c.PlayerEffects) != 0 || len(c.EnemyEffects) != 0 || c.LastEffects.Any() {
+		t.Errorf("after ClearEffects: %+v, %+v, %+v", c.PlayerEffects, c.EnemyEffects, c.LastEffects)
+	}
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"github.com/erwaen/type-glish/internal/cefr"
+)
+
+const (
+	AdaptiveWindow       = 6   // Number of recent grammar scores considered
+	AdaptiveSuccessScore = 7   // A score at or above this counts as a success
+	AdaptiveTargetRate   = 0.6 // Success rate the game tries to keep players around
+	adaptiveTolerance    = 0.2 // How far from the target before the level moves
+	maxAdaptiveShift     = 2   // Maximum bands above or below the configured difficulty
+)
+
+// Adaptive tracks a rolling window of grammar scores and shifts the
+// difficulty so players stay around the target success rate
+type Adaptive struct {
+	Scores []int // Most recent scores, oldest first
+	Shift  int   // Bands above (+) or below (-) the configured difficulty
+}
+
+// Record adds a score to the window. When adjust is true and the window
+// is full, the shift moves one band towards the target success rate.
+func (a *Adaptive) Record(score int, adjust bool) {
+	a.Scores = append(a.Scores, score)
+	if len(a.Scores) > AdaptiveWindow {
+		a.Scores = a.Scores[len(a.Scores)-AdaptiveWindow:]
+	}
+
+	if !adjust || len(a.Scores) < AdaptiveWindow {
+		return
+	}
+
+	rate := a.SuccessRate()
+	switch {
+	case rate > AdaptiveTargetRate+adaptiveTolerance && a.Shift < maxAdaptiveShift:
+		a.Shift++
+	case rate < AdaptiveTargetRate-adaptiveTolerance && a.Shift > -maxAdaptiveShift:
+		a.Shift--
+	default:
+		return
+	}
+	// Start a fresh window so one streak doesn't move the level twice
+	a.Scores = nil
+}
+
+// SuccessRate returns the share of scores in the window that count as a success
+func (a *Adaptive) SuccessRate() float64 {
+	if len(a.Scores) == 0 {
+		return 0
+	}
+	successes := 0
+	for _, s := range a.Scores {
+		if s >= AdaptiveSuccessScore {
+			successes++
+		}
+	}
+	return float64(successes) / float64(len(a.Scores))
+}
+
+// Level shifts the base level by the adaptive shift, staying within A1-C2
+func (a *Adaptive) Level(base cefr.Level) cefr.Level {
+	level := base
+	for i := 0; i < a.Shift; i++ {
+		if level == cefr.C2 {
+			break
+		}
+		level = level.Next()
+	}
+	for i := 0; i > a.Shift; i-- {
+		if level == cefr.A1 {
+			break
+		}
+		level = level.Prev()
+	}
+	return level
+}
+
+// TierRange returns the enemy tiers allowed at the current shift
+func (a *Adaptive) TierRange() (minTier, maxTier int) {
+	minTier, maxTier = 1, 3+a.Shift
+	if a.Shift >= maxAdaptiveShift {
+		minTier = 2
+	}
+	if maxTier < 1 {
+		maxTier = 1
+	}
+	if maxTier > 4 {
+		maxTier = 4
+	}
+	return minTier, maxTier
+}
@@ -0,0 +1,63 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"github.com/erwaen/type-glish/internal/adventure"
+	"github.com/erwaen/type-glish/internal/llm"
+)
+
+// AdventureRepeatComment replaces the DM comment of a repeated action
+const AdventureRepeatComment = "Again? The story has already moved past that. Try something new."
+
+const (
+	// adventureRecapActions is how many earlier outcomes the DM sees
+	adventureRecapActions = 5
+	// adventureRepeatWindow is how many earlier actions a repeat is checked against
+	adventureRepeatWindow = 20
+)
+
+// ActionRequest builds the LLM request for a Free Adventure action
+func (c *Context) ActionRequest(action string) llm.ActionRequest {
+	return llm.ActionRequest{
+		Action:  action,
+		Level:   c.Level(),
+		Persona: c.Persona,
+		Recap:   c.Journal.Recap(adventureRecapActions),
+	}
+}
+
+// RecordAdventure applies the rules to a Free Adventure assessment, scores
+// it in the journal and saves the journal. Repeats of recent actions score
+// no points.
+func (c *Context) RecordAdventure(a *llm.Assessment) adventure.Entry {
+	ValidateAssessment(a)
+
+	repeat := false
+	for _, input := range c.Journal.Inputs(adventureRepeatWindow) {
+		if c.AntiFarm.IsRepeat(c.LastInput, input) {
+			repeat = true
+			break
+		}
+	}
+	if repeat && !a.InjectionDetected {
+		a.DMComment = AdventureRepeatComment
+	}
+
+	entry := c.Journal.Record(adventure.Entry{
+		Time:      time.Now(),
+		Input:     c.LastInput,
+		Corrected: a.CorrectedSentence,
+		Score:     a.GrammarScore,
+		DMComment: a.DMComment,
+		Outcome:   a.OutcomeDescription,
+		Offline:   a.Offline,
+		Repeat:    repeat,
+		Injection: a.InjectionDetected,
+	})
+	if err := c.Journal.Save(); err != nil {
+		log.Printf("Error saving adventure journal: %v", err)
+	}
+	return entry
+}
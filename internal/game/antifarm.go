@@ -0,0 +1,97 @@
+package game
+
+import (
+	"math"
+	"strings"
+
+	"github.com/erwaen/type-glish/internal/config"
+	"github.com/erwaen/type-glish/internal/runlog"
+	"github.com/erwaen/type-glish/internal/textsim"
+)
+
+// Default anti-farming thresholds, used when the config leaves them at zero
+const (
+	DefaultFarmSimilarity   = 0.85
+	DefaultFarmNGramOverlap = 0.75
+	DefaultFarmDecay        = 0.5
+
+	farmNGramSize = 2
+	// farmMinWords is the shortest sentence compared fuzzily; shorter ones
+	// only repeat when they are identical
+	farmMinWords = 4
+)
+
+// FarmComment is the DM line when the player repeats a sentence
+const FarmComment = "Again? I have heard that line before, adventurer. Your blade dulls with every echo. Surprise me with something new."
+
+// AntiFarm detects exact and near-duplicate combat sentences within a run
+// and decides how much their damage is reduced
+type AntiFarm struct {
+	Similarity   float64
+	NGramOverlap float64
+	Decay        float64
+}
+
+// NewAntiFarm builds the rules from the config, filling in defaults
+func NewAntiFarm(cfg config.AntiFarm) AntiFarm {
+	f := AntiFarm{
+		Similarity:   cfg.Similarity,
+		NGramOverlap: cfg.NGramOverlap,
+		Decay:        cfg.Decay,
+	}
+	if f.Similarity <= 0 || f.Similarity > 1 {
+		f.Similarity = DefaultFarmSimilarity
+	}
+	if f.NGramOverlap <= 0 || f.NGramOverlap > 1 {
+		f.NGramOverlap = DefaultFarmNGramOverlap
+	}
+	if f.Decay <= 0 || f.Decay > 1 {
+		f.Decay = DefaultFarmDecay
+	}
+	return f
+}
+
+// IsRepeat reports whether two sentences are exact or near duplicates
+func (f AntiFarm) IsRepeat(a, b string) bool {
+	a = textsim.StripPunctuation(textsim.Normalize(a))
+	b = textsim.StripPunctuation(textsim.Normalize(b))
+	if a == "" || b == "" {
+		return false
+	}
+	if a == b {
+		return true
+	}
+	if min(len(strings.Fields(a)), len(strings.Fields(b))) < farmMinWords {
+		return false
+	}
+	return textsim.Similarity(a, b) >= f.Similarity ||
+		textsim.NGramOverlap(a, b, farmNGramSize) >= f.NGramOverlap
+}
+
+// Repeats counts the earlier combat sentences of the run that the input
+// repeats
+func (f AntiFarm) Repeats(input string, run *runlog.Run) int {
+	if run == nil {
+		return 0
+	}
+	count := 0
+	for _, t := range run.Turns {
+		if t.Kind == runlog.KindCombat && f.IsRepeat(input, t.Input) {
+			count++
+		}
+	}
+	return count
+}
+
+// Multiplier returns the damage multiplier after a number of repeats
+func (f AntiFarm) Multiplier(repeats int) float64 {
+	return math.Pow(f.Decay, float64(repeats))
+}
+
+// Reduce applies diminishing returns to the damage of a repeated sentence
+func (f AntiFarm) Reduce(damage, repeats int) int {
+	if repeats <= 0 || damage <= 0 {
+		return damage
+	}
+	return int(math.Floor(float64(damage) * f.Multiplier(repeats)))
+}
@@ -0,0 +1,139 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/erwaen/type-glish/internal/config"
+	"github.com/erwaen/type-glish/internal/runlog"
+)
+
+func TestNewAntiFarm(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.AntiFarm
+		want AntiFarm
+	}{
+		{
+			name: "zero config uses the defaults",
+			want: AntiFarm{DefaultFarmSimilarity, DefaultFarmNGramOverlap, DefaultFarmDecay},
+		},
+		{
+			name: "configured thresholds are kept",
+			cfg:  config.AntiFarm{Similarity: 0.9, NGramOverlap: 0.6, Decay: 0.25},
+			want: AntiFarm{0.9, 0.6, 0.25},
+		},
+		{
+			name: "out of range values fall back to the defaults",
+			cfg:  config.AntiFarm{Similarity: 1.5, NGramOverlap: -0.2, Decay: 2},
+			want: AntiFarm{DefaultFarmSimilarity, DefaultFarmNGramOverlap, DefaultFarmDecay},
+		},
+		{
+			name: "1 is a valid threshold",
+			cfg:  config.AntiFarm{Similarity: 1, NGramOverlap: 1, Decay: 1},
+			want: AntiFarm{1, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewAntiFarm(tt.cfg); got != tt.want {
+				t.Errorf("NewAntiFarm(%+v) = %+v, want %+v", tt.cfg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRepeat(t *testing.T) {
+	const sword = "I attack the goblin with my sword."
+	defaults := NewAntiFarm(config.AntiFarm{})
+
+	tests := []struct {
+		name string
+		farm AntiFarm
+		a, b string
+		want bool
+	}{
+		{"exact repeat", defaults, sword, sword, true},
+		{"case, spacing and punctuation", defaults, sword, "  i ATTACK the goblin with my sword!!", true},
+		{"empty input", defaults, "", sword, false},
+		{"punctuation only", defaults, "...", "!!!", false},
+		{"different sentence", defaults, sword, "I swing my sword at the goblin.", false},
+		{"short exact repeat", defaults, "I attack.", "i attack", true},
+		{"short near repeat is not compared", defaults, "I attack.", "I attacks.", false},
+		{"short opener of a longer sentence", defaults, "I attack.", sword, false},
+
+		// "swords" is 0.97 similar with 5 of 6 bigrams shared
+		{"one letter added", defaults, sword, "I attack the goblin with my swords.", true},
+		{"similarity just reached", AntiFarm{Similarity: 0.97, NGramOverlap: 1}, sword, "I attack the goblin with my swords.", true},
+		{"similarity just missed", AntiFarm{Similarity: 0.98, NGramOverlap: 1}, sword, "I attack the goblin with my swords.", false},
+		{"overlap just reached", AntiFarm{Similarity: 1, NGramOverlap: 0.83}, sword, "I attack the goblin with my swords.", true},
+		{"overlap just missed", AntiFarm{Similarity: 1, NGramOverlap: 0.84}, sword, "I attack the goblin with my swords.", false},
+
+		// "axe" is 0.85 similar with 5 of 6 bigrams shared
+		{"weapon swapped", defaults, sword, "I attack the goblin with my axe.", true},
+		{"weapon swapped, strict overlap", AntiFarm{Similarity: 0.9, NGramOverlap: 0.9}, sword, "I attack the goblin with my axe.", false},
+
+		// Two words added: 0.75 similar with 6 of 8 bigrams shared
+		{"words added, default overlap", defaults, sword, "I attack the goblin with my sword and shield.", true},
+		{"words added, configured overlap", AntiFarm{Similarity: 0.85, NGramOverlap: 0.8}, sword, "I attack the goblin with my sword and shield.", false},
+		{"words added, configured similarity", AntiFarm{Similarity: 0.75, NGramOverlap: 1}, sword, "I attack the goblin with my sword and shield.", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.farm.IsRepeat(tt.a, tt.b); got != tt.want {
+				t.Errorf("IsRepeat(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := tt.farm.IsRepeat(tt.b, tt.a); got != tt.want {
+				t.Errorf("IsRepeat(%q, %q) = %v, want %v", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepeats(t *testing.T) {
+	f := NewAntiFarm(config.AntiFarm{})
+	run := &runlog.Run{Turns: []runlog.Turn{
+		{Kind: runlog.KindCombat, Input: "I attack the goblin with my sword."},
+		{Kind: runlog.KindPath, Input: "I attack the goblin with my sword."},
+		{Kind: runlog.KindCombat, Input: "I cast a fireball at the troll."},
+		{Kind: runlog.KindCombat, Input: "I attack the goblin with my swords."},
+	}}
+
+	tests := []struct {
+		input string
+		run   *runlog.Run
+		want  int
+	}{
+		{"I attack the goblin with my sword.", nil, 0},
+		{"I attack the goblin with my sword.", run, 2},
+		{"I cast a fireball at the troll!", run, 1},
+		{"I hide behind the old oak tree.", run, 0},
+	}
+	for _, tt := range tests {
+		if got := f.Repeats(tt.input, tt.run); got != tt.want {
+			t.Errorf("Repeats(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestReduce(t *testing.T) {
+	tests := []struct {
+		decay           float64
+		damage, repeats int
+		want            int
+	}{
+		{0.5, 15, 0, 15},
+		{0.5, 15, 1, 7},
+		{0.5, 15, 2, 3},
+		{0.5, 15, 4, 0},
+		{0.5, 0, 3, 0},
+		{0.5, -2, 1, -2},
+		{0.25, 16, 1, 4},
+		{1, 15, 5, 15},
+	}
+	for _, tt := range tests {
+		f := AntiFarm{Decay: tt.decay}
+		if got := f.Reduce(tt.damage, tt.repeats); got != tt.want {
+			t.Errorf("Reduce(%d, %d) with decay %v = %d, want %d", tt.damage, tt.repeats, tt.decay, got, tt.want)
+		}
+	}
+}
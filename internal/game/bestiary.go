@@ -0,0 +1,172 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erwaen/type-glish/internal/config"
+	"github.com/erwaen/type-glish/internal/llm"
+)
+
+const bestiaryFileName = "bestiary.json"
+
+// Bestiary limits
+const (
+	// BestiaryTarget is the size below which a new run asks for more enemies
+	BestiaryTarget = 12
+	// MaxBestiary is the most generated enemies kept, oldest dropped first
+	MaxBestiary = 40
+	// bestiaryBatch is how many enemies one generation call asks for
+	bestiaryBatch = 4
+	// GeneratedShare is the chance that a spawn picks a generated enemy
+	GeneratedShare = 0.4
+	// maxGeneratedTier keeps bosses handcrafted
+	maxGeneratedTier = 3
+)
+
+// tierHP is the max HP of generated enemies per tier, in line with the
+// predefined ones, so the model never decides how tough an enemy is
+var tierHP = map[int]int{1: 22, 2: 35, 3: 50}
+
+// Bestiary holds the enemies generated by the LLM, persisted in the
+// config directory
+type Bestiary struct {
+	mu      sync.Mutex
+	Enemies []Enemy `json:"enemies"`
+}
+
+// LoadBestiary reads the bestiary, or returns an empty one
+func LoadBestiary() (*Bestiary, error) {
+	b := &Bestiary{}
+	path, err := config.GetDataPath(bestiaryFileName)
+	if err != nil {
+		return b, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return b, nil
+		}
+		return b, err
+	}
+	if err := json.Unmarshal(data, b); err != nil {
+		return &Bestiary{}, fmt.Errorf("failed to parse bestiary: %w", err)
+	}
+	return b, nil
+}
+
+func (b *Bestiary) save() error {
+	path, err := config.GetDataPath(bestiaryFileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bestiary: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Len returns the number of generated enemies
+func (b *Bestiary) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.Enemies)
+}
+
+// Names returns the names of every predefined and generated enemy
+func (b *Bestiary) Names() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var names []string
+	for _, e := range Enemies {
+		names = append(names, e.Name)
+	}
+	names = append(names, MemoryWraith.Name)
+	for _, e := range b.Enemies {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+// BalanceEnemy turns a validated draft into an enemy. Stats come from the
+// game rules: the tier is capped below the bosses and the HP follows the
+// tier.
+func BalanceEnemy(d llm.EnemyDraft) Enemy {
+	tier := min(max(d.Tier, 1), maxGeneratedTier)
+	return Enemy{
+		Name:         d.Name,
+		HP:           tierHP[tier],
+		MaxHP:        tierHP[tier],
+		Tier:         tier,
+		Location:     d.Location,
+		Description:  d.Description,
+		GrammarFocus: d.GrammarFocus,
+		Generated:    time.Now(),
+	}
+}
+
+// Add balances the drafts and adds those with a new name, then saves the
+// bestiary. It returns how many were added.
+func (b *Bestiary) Add(drafts []llm.EnemyDraft) (int, error) {
+	taken := map[string]bool{}
+	for _, name := range b.Names() {
+		taken[strings.ToLower(name)] = true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	added := 0
+	for _, d := range drafts {
+		key := strings.ToLower(d.Name)
+		if taken[key] {
+			continue
+		}
+		taken[key] = true
+		b.Enemies = append(b.Enemies, BalanceEnemy(d))
+		added++
+	}
+	if len(b.Enemies) > MaxBestiary {
+		b.Enemies = b.Enemies[len(b.Enemies)-MaxBestiary:]
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	return added, b.save()
+}
+
+// Random returns a copy of a random generated enemy whose tier is between
+// minTier and maxTier, or nil if there is none
+func (b *Bestiary) Random(minTier, maxTier int) *Enemy {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var pool []Enemy
+	for _, e := range b.Enemies {
+		if e.Tier >= minTier && e.Tier <= maxTier {
+			pool = append(pool, e)
+		}
+	}
+	if len(pool) == 0 {
+		return nil
+	}
+	return newEnemy(pool[rand.Intn(len(pool))])
+}
+
+// GrowBestiary asks the LLM for a batch of new enemies when the bestiary
+// is below BestiaryTarget. It returns how many were added.
+func (c *Context) GrowBestiary() (int, error) {
+	if c.Bestiary.Len() >= BestiaryTarget {
+		return 0, nil
+	}
+	drafts, err := c.LLMClient.GenerateEnemies(bestiaryBatch, c.Bestiary.Names(), c.Persona)
+	if err != nil {
+		return 0, err
+	}
+	return c.Bestiary.Add(drafts)
+}
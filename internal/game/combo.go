@@ -0,0 +1,74 @@
+package game
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/erwaen/type-glish/internal/llm"
+)
+
+// Combo and critical hit rules
+const (
+	// ComboScore is the lowest score that builds the combo; anything lower
+	// breaks it
+	ComboScore = 8
+	// ComboStep is the extra damage per hit of the combo before this one
+	ComboStep = 0.1
+	// MaxComboMultiplier caps the combo damage
+	MaxComboMultiplier = 1.5
+	// CriticalChance is the chance that a perfect 10 is a critical hit
+	CriticalChance = 0.25
+	// CriticalMultiplier is the damage of a critical hit
+	CriticalMultiplier = 2.0
+)
+
+// ComboMultiplier returns the damage multiplier of a hit that extends a
+// combo of the given length
+func ComboMultiplier(combo int) float64 {
+	return min(1+ComboStep*float64(combo), MaxComboMultiplier)
+}
+
+// Strike is what the combo and critical rules did to a combat turn, for
+// the result screen
+type Strike struct {
+	Combo      int     // Combo after this turn, 0 if it broke
+	Multiplier float64 // Combo multiplier applied, 1 if none
+	Critical   bool
+	Bonus      int // Damage added by the combo and the critical hit
+	Broken     int // Length of the combo this turn broke, 0 if none
+}
+
+// applyStrike updates the combo with a validated assessment and scales
+// its damage. Injection attempts and repeated sentences break the combo
+// like a low score does. The combo lasts for the whole run, across
+// enemies.
+func (c *Context) applyStrike(a *llm.CombatAssessment) Strike {
+	if a.InjectionDetected || a.Repeats > 0 || a.GrammarScore < ComboScore {
+		st := Strike{Multiplier: 1, Broken: c.Combo}
+		if st.Broken < 2 {
+			st.Broken = 0
+		}
+		c.Combo = 0
+		return st
+	}
+
+	st := Strike{Multiplier: ComboMultiplier(c.Combo)}
+	damage := float64(a.DamageDealt) * st.Multiplier
+	if a.GrammarScore == MaxScore && rand.Float64() < CriticalChance {
+		st.Critical = true
+		damage *= CriticalMultiplier
+	}
+	scaled := int(math.Round(damage))
+	st.Bonus = scaled - a.DamageDealt
+	a.DamageDealt = scaled
+
+	c.Combo++
+	st.Combo = c.Combo
+	if c.Run != nil {
+		c.Run.BestCombo = max(c.Run.BestCombo, c.Combo)
+		if st.Critical {
+			c.Run.Criticals++
+		}
+	}
+	return st
+}
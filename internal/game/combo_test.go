@@ -0,0 +1,183 @@
+package game
+
+import (
+	"math"
+	"testing"
+
+	"github.com/erwaen/type-glish/internal/runlog"
+)
+
+func TestComboMultiplier(t *testing.T) {
+	tests := []struct {
+		combo int
+		want  float64
+	}{
+		{0, 1},
+		{1, 1.1},
+		{3, 1.3},
+		{5, MaxComboMultiplier},
+		{20, MaxComboMultiplier},
+	}
+	for _, tt := range tests {
+		if got := ComboMultiplier(tt.combo); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("ComboMultiplier(%d) = %v, want %v", tt.combo, got, tt.want)
+		}
+	}
+}
+
+func TestComboStreak(t *testing.T) {
+	type turn struct {
+		score, dealt int
+		injection    bool
+	}
+	tests := []struct {
+		name       string
+		turns      []turn
+		wantDealt  []int
+		wantCombo  []int
+		wantBroken []int
+		wantBest   int
+	}{
+		{
+			name:       "high scores build the combo",
+			turns:      []turn{{8, 10, false}, {9, 10, false}, {8, 10, false}, {9, 10, false}},
+			wantDealt:  []int{10, 11, 12, 13},
+			wantCombo:  []int{1, 2, 3, 4},
+			wantBroken: []int{0, 0, 0, 0},
+			wantBest:   4,
+		},
+		{
+			name:       "low score breaks the combo",
+			turns:      []turn{{8, 10, false}, {9, 10, false}, {8, 10, false}, {7, 10, false}, {8, 10, false}},
+			wantDealt:  []int{10, 11, 12, 10, 10},
+			wantCombo:  []int{1, 2, 3, 0, 1},
+			wantBroken: []int{0, 0, 0, 3, 0},
+			wantBest:   3,
+		},
+		{
+			name:       "breaking a single hit is not reported",
+			turns:      []turn{{8, 10, false}, {5, 7, false}},
+			wantDealt:  []int{10, 7},
+			wantCombo:  []int{1, 0},
+			wantBroken: []int{0, 0},
+			wantBest:   1,
+		},
+		{
+			name:       "injection breaks the combo",
+			turns:      []turn{{9, 10, false}, {9, 10, false}, {9, 10, true}, {9, 10, false}},
+			wantDealt:  []int{10, 11, 0, 10},
+			wantCombo:  []int{1, 2, 0, 1},
+			wantBroken: []int{0, 0, 2, 0},
+			wantBest:   2,
+		},
+		{
+			name: "multiplier is capped",
+			turns: []turn{
+				{9, 10, false}, {9, 10, false}, {9, 10, false}, {9, 10, false},
+				{9, 10, false}, {9, 10, false}, {9, 10, false},
+			},
+			wantDealt:  []int{10, 11, 12, 13, 14, 15, 15},
+			wantCombo:  []int{1, 2, 3, 4, 5, 6, 7},
+			wantBroken: []int{0, 0, 0, 0, 0, 0, 0},
+			wantBest:   7,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFight(MemoryWraith.Name, 500)
+			for i, tu := range tt.turns {
+				a := hit(tu.score, tu.dealt, 3, "none")
+				a.InjectionDetected = tu.injection
+				c.ApplyCombatAssessment(a)
+
+				if a.DamageDealt != tt.wantDealt[i] {
+					t.Errorf("turn %d: dealt %d, want %d", i+1, a.DamageDealt, tt.wantDealt[i])
+				}
+				if c.Combo != tt.wantCombo[i] || c.LastStrike.Combo != tt.wantCombo[i] {
+					t.Errorf("turn %d: combo %d (strike %d), want %d", i+1, c.Combo, c.LastStrike.Combo, tt.wantCombo[i])
+				}
+				if c.LastStrike.Broken != tt.wantBroken[i] {
+					t.Errorf("turn %d: broken %d, want %d", i+1, c.LastStrike.Broken, tt.wantBroken[i])
+				}
+			}
+			if c.Run.BestCombo != tt.wantBest {
+				t.Errorf("BestCombo = %d, want %d", c.Run.BestCombo, tt.wantBest)
+			}
+		})
+	}
+}
+
+func TestComboBrokenByRepeat(t *testing.T) {
+	const sentence = "I strike the wraith with a silver blade."
+	c := newFight(MemoryWraith.Name, 500)
+	c.ApplyCombatAssessment(hit(9, 10, 3, "none"))
+	c.ApplyCombatAssessment(hit(9, 10, 3, "none"))
+
+	c.Run.Turns = []runlog.Turn{{Kind: runlog.KindCombat, Input: sentence}}
+	c.LastInput = sentence
+	a := hit(10, 14, 3, "none")
+	c.ApplyCombatAssessment(a)
+
+	if c.Combo != 0 || c.LastStrike.Broken != 2 || c.LastStrike.Critical {
+		t.Errorf("LastStrike = %+v, want the combo of 2 broken without a critical", c.LastStrike)
+	}
+	if a.DamageDealt != 7 {
+		t.Errorf("dealt %d, want the repeat damage of 7 without a combo bonus", a.DamageDealt)
+	}
+}
+
+func TestComboLastsAcrossEnemies(t *testing.T) {
+	c := newFight("Goblin", 5)
+	c.ApplyCombatAssessment(hit(8, 10, 3, "none"))
+	if c.CurrentEnemy.HP != 0 {
+		t.Fatalf("enemy HP = %d, want the first enemy dead", c.CurrentEnemy.HP)
+	}
+
+	c.CurrentEnemy = &Enemy{Name: "Troll", HP: 100, MaxHP: 100, Tier: 2}
+	c.CombatHistory = nil
+	c.ClearEffects()
+	a := hit(8, 10, 3, "none")
+	c.ApplyCombatAssessment(a)
+	if c.Combo != 2 || a.DamageDealt != 11 {
+		t.Errorf("combo %d, dealt %d, want the combo carried over", c.Combo, a.DamageDealt)
+	}
+
+	c.StartRun()
+	if c.Combo != 0 || c.LastStrike != (Strike{}) {
+		t.Errorf("after StartRun combo %d, strike %+v, want both reset", c.Combo, c.LastStrike)
+	}
+}
+
+func TestCriticalHits(t *testing.T) {
+	criticals := 0
+	for range 400 {
+		before := criticals
+		c := newFight(MemoryWraith.Name, 500)
+		a := hit(MaxScore, 15, 3, "none")
+		c.ApplyCombatAssessment(a)
+
+		switch {
+		case c.LastStrike.Critical && a.DamageDealt == 30 && c.LastStrike.Bonus == 15:
+			criticals++
+		case !c.LastStrike.Critical && a.DamageDealt == 15 && c.LastStrike.Bonus == 0:
+		default:
+			t.Fatalf("dealt %d with strike %+v, want 15 or a 30 critical", a.DamageDealt, c.LastStrike)
+		}
+		if wantCriticals := criticals - before; c.Run.Criticals != wantCriticals {
+			t.Fatalf("Run.Criticals = %d, want %d", c.Run.Criticals, wantCriticals)
+		}
+	}
+	// CriticalChance is 25%, so 400 hits land far from both bounds
+	if criticals < 50 || criticals > 150 {
+		t.Errorf("%d criticals in 400 perfect hits, want about 100", criticals)
+	}
+
+	// Only perfect scores can be critical
+	for range 100 {
+		c := newFight(MemoryWraith.Name, 500)
+		c.ApplyCombatAssessment(hit(9, 13, 3, "none"))
+		if c.LastStrike.Critical {
+			t.Fatal("score 9 was a critical hit")
+		}
+	}
+}
@@ -3,9 +3,18 @@ package game
 import (
 	"context"
 	"fmt"
+	"log"
+	"math/rand"
+	"time"
 
+	"github.com/erwaen/type-glish/internal/adventure"
+	"github.com/erwaen/type-glish/internal/cefr"
 	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/llm"
+	"github.com/erwaen/type-glish/internal/review"
+	"github.com/erwaen/type-glish/internal/runlog"
+	"github.com/erwaen/type-glish/internal/textsim"
+	"github.com/erwaen/type-glish/internal/vocab"
 )
 
 type PlayerStats struct {
@@ -19,7 +28,7 @@ type PlayerStats struct {
 
 type Context struct {
 	Stats            PlayerStats
-	History          []llm.ChatMessage
+	Run              *runlog.Run // Log of the current run, nil before the first game
 	LastInput        string
 	LastAssessment   llm.Assessment       // structure result from the llm
 	CombatAssessment llm.CombatAssessment // combat-specific result from the llm
@@ -27,8 +36,62 @@ type Context struct {
 	LLMClient        *llm.Client
 
 	// Combat state
-	CurrentEnemy *Enemy
-	Location     string
+	CurrentEnemy    *Enemy
+	Location        string
+	QuickStrikeUsed bool             // A typing quick strike was already used on this enemy
+	CombatHistory   []llm.CombatTurn // Turns against the current enemy, for the DM's memory
+
+	// Status effects of the current fight, resolved by the rules
+	PlayerEffects Effects
+	EnemyEffects  Effects
+	LastEffects   TurnEffects // What abilities and statuses did last turn
+
+	// Consecutive combat scores of ComboScore or more in the current run
+	Combo      int
+	LastStrike Strike // What the combo and critical rules did last turn
+
+	// Difficulty as a CEFR band, from the config
+	Difficulty cefr.Level
+
+	// Adaptive difficulty based on rolling grammar performance
+	AdaptiveEnabled bool
+	Adaptive        Adaptive
+
+	// Corrected sentences scheduled for spaced-repetition review
+	Deck *review.Deck
+
+	// Collected vocabulary, persisted across runs
+	Vocab *vocab.Book
+
+	// Story and score of the Free Adventure mode
+	Journal *adventure.Journal
+
+	// Learner's native language for grammar explanations, empty if disabled
+	ExplanationLanguage string
+
+	// DM persona ID, from the config
+	Persona string
+
+	// LLM-generated enemies, mixed into spawns when GenerateEnemies is on
+	Bestiary        *Bestiary
+	GenerateEnemies bool
+
+	// LLM narration of scene transitions, from the config
+	Narration bool
+
+	// Diminishing damage for repeated sentences
+	AntiFarm AntiFarm
+
+	// Token usage of the LLM providers, and the meter that enforces the
+	// daily budget
+	Usage *llm.UsageLedger
+	Meter *llm.MeteredProvider
+
+	// Retries and rate limiting, watched by the processing spinners
+	Retry *llm.RetryProvider
+
+	// Latency and error counters of the LLM calls this session
+	Metrics *llm.Metrics
 
 	// Error tracking (for display)
 	LastError string
@@ -40,14 +103,86 @@ type Context struct {
 
 func NewContext(cfg *config.Config) *Context {
 	ctx := &Context{
-		Stats: PlayerStats{HP: 100, Level: 1},
+		Stats:           PlayerStats{HP: 100, Level: 1},
+		Difficulty:      cefr.Parse(cfg.Difficulty),
+		AdaptiveEnabled: cfg.AdaptiveDifficulty,
+
+		ExplanationLanguage: cfg.ExplanationLanguage,
+		Persona:             cfg.Persona,
+		GenerateEnemies:     cfg.GenerateEnemies,
+		Narration:           !cfg.DisableNarration,
+		AntiFarm:            NewAntiFarm(cfg.AntiFarm),
+	}
+
+	deck, err := review.LoadDeck()
+	if err != nil {
+		log.Printf("Error loading review deck: %v", err)
+		deck = &review.Deck{}
 	}
+	ctx.Deck = deck
+
+	book, err := vocab.LoadBook()
+	if err != nil {
+		log.Printf("Error loading vocabulary: %v", err)
+		book = &vocab.Book{}
+	}
+	ctx.Vocab = book
+
+	journal, err := adventure.LoadJournal()
+	if err != nil {
+		log.Printf("Error loading adventure journal: %v", err)
+		journal = &adventure.Journal{}
+	}
+	ctx.Journal = journal
+
+	bestiary, err := LoadBestiary()
+	if err != nil {
+		log.Printf("Error loading bestiary: %v", err)
+	}
+	ctx.Bestiary = bestiary
+
+	usage, err := llm.LoadUsage()
+	if err != nil {
+		log.Printf("Error loading usage: %v", err)
+	}
+	ctx.Usage = usage
+	ctx.Metrics = llm.NewMetrics()
+
 	ctx.ReloadLLM(cfg)
 	return ctx
 }
 
 // ReloadLLM recreates the LLM client based on the provided config
 func (c *Context) ReloadLLM(cfg *config.Config) {
+	provider := NewProvider(cfg)
+
+	// Paid providers fall back to the local one once the budget is spent
+	var fallback llm.Provider
+	if llm.IsPaid(provider.Name()) {
+		fallback = llm.NewLlamaCppProvider()
+	}
+	c.Meter = llm.NewMeteredProvider(provider, fallback, c.Usage, cfg.DailyTokenBudget)
+	c.Retry = llm.NewRetryProvider(c.Meter, cfg.MaxRetries, cfg.RequestsPerMinute)
+
+	// Outermost first: spans and logs cover cache hits and retries too
+	var middlewares []llm.Middleware
+	if cfg.Tracing {
+		middlewares = append(middlewares, llm.WithTracing())
+	}
+	middlewares = append(middlewares, llm.WithLogging(cfg.LogPrompts, cfg.LogResponses), llm.WithMetrics(c.Metrics))
+	if !cfg.DisableCache {
+		if dir, err := config.GetDataPath("cache"); err == nil {
+			ttl := time.Duration(cfg.CacheTTLHours) * time.Hour
+			middlewares = append(middlewares, llm.WithCache(dir, ttl, int64(cfg.CacheMaxMB)<<20))
+		}
+	}
+
+	c.LLMClient = llm.NewClient(llm.Chain(c.Retry, middlewares...))
+}
+
+// NewProvider creates the LLM provider selected in the config, falling
+// back to llama.cpp when Gemini can't be used
+func NewProvider(cfg *config.Config) llm.Provider {
 	var provider llm.Provider
 	var err error
 
@@ -66,5 +201,124 @@ func (c *Context) ReloadLLM(cfg *config.Config) {
 		provider = llm.NewLlamaCppProvider()
 	}
 
-	c.LLMClient = llm.NewClient(provider)
+	return provider
+}
+
+// Level returns the difficulty in effect, including the adaptive shift
+func (c *Context) Level() cefr.Level {
+	if !c.AdaptiveEnabled {
+		return c.Difficulty
+	}
+	return c.Adaptive.Level(c.Difficulty)
+}
+
+// SpawnEnemy picks a random enemy scaled to the current difficulty. With
+// generated enemies on, GeneratedShare of the spawns come from the bestiary.
+func (c *Context) SpawnEnemy() *Enemy {
+	minTier, maxTier := 1, 4
+	if c.AdaptiveEnabled {
+		minTier, maxTier = c.Adaptive.TierRange()
+	}
+
+	var enemy *Enemy
+	if c.GenerateEnemies && rand.Float64() < GeneratedShare {
+		enemy = c.Bestiary.Random(minTier, maxTier)
+	}
+	if enemy == nil {
+		if c.AdaptiveEnabled {
+			enemy = RandomEnemyInTiers(minTier, maxTier)
+		} else {
+			enemy = RandomEnemy()
+		}
+	}
+	ScaleEnemy(enemy, c.Level())
+	c.CurrentEnemy = enemy
+	c.QuickStrikeUsed = false
+	c.CombatHistory = nil
+	c.ClearEffects()
+	return enemy
+}
+
+// RecordCorrection stores a corrected sentence as a review card and
+// collects the vocabulary the DM picked out
+func (c *Context) RecordCorrection(original string, a llm.CombatAssessment) {
+	now := time.Now()
+
+	if a.CorrectedSentence != "" && textsim.Normalize(original) != textsim.Normalize(a.CorrectedSentence) {
+		c.Deck.Add(review.Card{
+			Original:    original,
+			Corrected:   a.CorrectedSentence,
+			Category:    a.ErrorCategory,
+			Comment:     a.DMComment,
+			Explanation: a.Explanation,
+		}, now)
+		if err := c.Deck.Save(); err != nil {
+			log.Printf("Error saving review deck: %v", err)
+		}
+	}
+
+	added := false
+	for _, word := range a.Vocabulary {
+		if c.Vocab.Add(word, a.CorrectedSentence, now) {
+			c.Stats.Vocabulary = append(c.Stats.Vocabulary, word)
+			added = true
+		}
+	}
+	if added {
+		if err := c.Vocab.Save(); err != nil {
+			log.Printf("Error saving vocabulary: %v", err)
+		}
+	}
+}
+
+// StartRun resets the player for a new game and starts a new run log.
+// Nothing from a previous run carries over: the adaptive shift, the
+// fight history and any status effects start fresh.
+func (c *Context) StartRun() {
+	c.Stats.HP = 100
+	c.Stats.XP = 0
+	c.Stats.Gold = 0
+	c.Combo = 0
+	c.LastStrike = Strike{}
+	c.Adaptive = Adaptive{}
+	c.CombatHistory = nil
+	c.ClearEffects()
+	c.Run = runlog.NewRun(string(c.Difficulty), time.Now())
+}
+
+// RecordTurn adds a turn to the run log and saves it
+func (c *Context) RecordTurn(t runlog.Turn) {
+	if c.Run == nil {
+		return
+	}
+	t.Time = time.Now()
+	if t.Enemy == "" && c.CurrentEnemy != nil && t.Kind != runlog.KindPath {
+		t.Enemy = c.CurrentEnemy.Name
+	}
+	c.Run.Add(t)
+	c.saveRun()
+}
+
+// RecordVictory counts a defeated enemy in the run log and saves it
+func (c *Context) RecordVictory() {
+	if c.Run == nil {
+		return
+	}
+	c.Run.EnemiesDefeated++
+	c.saveRun()
+}
+
+// EndRun marks the run log as finished and saves it
+func (c *Context) EndRun(result string) {
+	if c.Run == nil || !c.Run.Ended.IsZero() {
+		return
+	}
+	c.Run.End(result, time.Now())
+	c.saveRun()
+}
+
+func (c *Context) saveRun() {
+	if err := c.Run.Save(); err != nil {
+		log.Printf("Error saving run log: %v", err)
+	}
 }
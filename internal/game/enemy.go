@@ -2,80 +2,129 @@ package game
 
 import (
 	"math/rand"
+	"time"
 )
 
 type Enemy struct {
-	Name        string
-	HP          int
-	MaxHP       int
-	Tier        int // 1=easy, 2=medium, 3=hard, 4=boss
-	Location    string
-	Description string
+	Name         string    `json:"name"`
+	HP           int       `json:"hp"`
+	MaxHP        int       `json:"max_hp"`
+	Tier         int       `json:"tier"` // 1=easy, 2=medium, 3=hard, 4=boss
+	Location     string    `json:"location"`
+	Description  string    `json:"description"`
+	GrammarFocus string    `json:"grammar_focus,omitempty"` // Grammar area the enemy embodies, one of llm.GrammarFocuses
+	Generated    time.Time `json:"generated,omitzero"`      // When the LLM invented it, zero for predefined enemies
 }
 
 // Predefined enemies
 var Enemies = []Enemy{
 	{
-		Name:        "Goblin",
-		HP:          20,
-		MaxHP:       20,
-		Tier:        1,
-		Location:    "The Murky Swamp",
-		Description: "A sneaky goblin with a rusty dagger, muttering broken sentences.",
+		Name:         "Goblin",
+		HP:           20,
+		MaxHP:        20,
+		Tier:         1,
+		Location:     "The Murky Swamp",
+		Description:  "A sneaky goblin with a rusty dagger, muttering broken sentences.",
+		GrammarFocus: "articles",
 	},
 	{
-		Name:        "Syntax Spider",
-		HP:          25,
-		MaxHP:       25,
-		Tier:        1,
-		Location:    "The Web of Words",
-		Description: "A giant spider that weaves webs of confusing clauses.",
+		Name:         "Syntax Spider",
+		HP:           25,
+		MaxHP:        25,
+		Tier:         1,
+		Location:     "The Web of Words",
+		Description:  "A giant spider that weaves webs of confusing clauses.",
+		GrammarFocus: "word order",
 	},
 	{
-		Name:        "Skeleton",
-		HP:          30,
-		MaxHP:       30,
-		Tier:        2,
-		Location:    "The Crypt of Conjugations",
-		Description: "A rattling skeleton that speaks only in past tense.",
+		Name:         "Skeleton",
+		HP:           30,
+		MaxHP:        30,
+		Tier:         2,
+		Location:     "The Crypt of Conjugations",
+		Description:  "A rattling skeleton that speaks only in past tense.",
+		GrammarFocus: "verb tense",
 	},
 	{
-		Name:        "Dark Wizard",
-		HP:          40,
-		MaxHP:       40,
-		Tier:        2,
-		Location:    "The Tower of Tenses",
-		Description: "A hooded figure casting spells with perfectly structured incantations.",
+		Name:         "Dark Wizard",
+		HP:           40,
+		MaxHP:        40,
+		Tier:         2,
+		Location:     "The Tower of Tenses",
+		Description:  "A hooded figure casting spells with perfectly structured incantations.",
+		GrammarFocus: "conjunctions",
 	},
 	{
-		Name:        "Troll",
-		HP:          50,
-		MaxHP:       50,
-		Tier:        3,
-		Location:    "The Whispering Woods",
-		Description: "A massive troll with a wooden club. He mocks your grammar mistakes.",
+		Name:         "Troll",
+		HP:           50,
+		MaxHP:        50,
+		Tier:         3,
+		Location:     "The Whispering Woods",
+		Description:  "A massive troll with a wooden club. He mocks your grammar mistakes.",
+		GrammarFocus: "spelling",
 	},
 	{
-		Name:        "Grammar Golem",
-		HP:          75,
-		MaxHP:       75,
-		Tier:        4,
-		Location:    "The Lexicon Library",
-		Description: "A towering construct made of ancient dictionaries and thesauri.",
+		Name:         "Grammar Golem",
+		HP:           75,
+		MaxHP:        75,
+		Tier:         4,
+		Location:     "The Lexicon Library",
+		Description:  "A towering construct made of ancient dictionaries and thesauri.",
+		GrammarFocus: "word choice",
 	},
 }
 
+// MemoryWraith is a special encounter built from the player's own past
+// mistakes. It is not part of Enemies so RandomEnemy never picks it.
+var MemoryWraith = Enemy{
+	Name:        "Memory Wraith",
+	Tier:        2,
+	Location:    "The Hall of Forgotten Errors",
+	Description: "A pale spirit that whispers your old mistakes back at you.",
+}
+
+// MemoryWraithDamage is the damage dealt by each correctly retyped sentence
+const MemoryWraithDamage = 10
+
+// NewMemoryWraith returns a Memory Wraith with enough HP for the given
+// number of review cards
+func NewMemoryWraith(cards int) *Enemy {
+	wraith := newEnemy(MemoryWraith)
+	wraith.MaxHP = max(1, cards) * MemoryWraithDamage
+	wraith.HP = wraith.MaxHP
+	return wraith
+}
+
 // RandomEnemy returns a copy of a random enemy from the list
 func RandomEnemy() *Enemy {
 	idx := rand.Intn(len(Enemies))
-	enemy := Enemies[idx]
-	return &Enemy{
-		Name:        enemy.Name,
-		HP:          enemy.MaxHP,
-		MaxHP:       enemy.MaxHP,
-		Tier:        enemy.Tier,
-		Location:    enemy.Location,
-		Description: enemy.Description,
+	return newEnemy(Enemies[idx])
+}
+
+// RandomEnemyInTiers returns a copy of a random enemy whose tier is
+// between minTier and maxTier, or any enemy if none match
+func RandomEnemyInTiers(minTier, maxTier int) *Enemy {
+	var pool []Enemy
+	for _, e := range Enemies {
+		if e.Tier >= minTier && e.Tier <= maxTier {
+			pool = append(pool, e)
+		}
+	}
+	if len(pool) == 0 {
+		return RandomEnemy()
 	}
+	return newEnemy(pool[rand.Intn(len(pool))])
 }
 
+// newEnemy returns a fresh, full HP copy of an enemy template
+func newEnemy(enemy Enemy) *Enemy {
+	return &Enemy{
+		Name:         enemy.Name,
+		HP:           enemy.MaxHP,
+		MaxHP:        enemy.MaxHP,
+		Tier:         enemy.Tier,
+		Location:     enemy.Location,
+		Description:  enemy.Description,
+		GrammarFocus: enemy.GrammarFocus,
+	}
+}
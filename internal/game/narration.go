@@ -0,0 +1,93 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/erwaen/type-glish/internal/llm"
+)
+
+// Scene kinds, for narrated transitions
+const (
+	SceneArrival    = "arrival"    // Start of a run
+	SceneEncounter  = "encounter"  // A new enemy blocks the way
+	SceneCrossroads = "crossroads" // The player picks a path
+)
+
+// recapTurns is how many recent turns the narration recap covers
+const recapTurns = 3
+
+// recapOutcomeChars truncates outcomes in the recap
+const recapOutcomeChars = 120
+
+// Scene is a transition the DM narrates
+type Scene struct {
+	Kind      string
+	Enemy     *Enemy   // Arrival and encounter
+	Paths     []string // Crossroads, "Name: description"
+	Travelled bool     // Encounter right after choosing a path
+}
+
+// StaticText returns the fixed narration used when the LLM is off or
+// unreachable
+func (s Scene) StaticText() string {
+	switch s.Kind {
+	case SceneArrival:
+		return fmt.Sprintf("You enter the Kingdom of Lexicon, where words have power. A %s blocks your path! %s",
+			s.Enemy.Name, s.Enemy.Description)
+	case SceneEncounter:
+		if s.Travelled {
+			return fmt.Sprintf("As you travel, a %s blocks your path! %s", s.Enemy.Name, s.Enemy.Description)
+		}
+		return fmt.Sprintf("A %s appears! %s", s.Enemy.Name, s.Enemy.Description)
+	case SceneCrossroads:
+		return "The road splits before you. Each path promises rest, if you can describe your choice well."
+	}
+	return ""
+}
+
+// NarrationRequest builds the LLM request for the scene, with the run so
+// far as context
+func (c *Context) NarrationRequest(s Scene) llm.NarrationRequest {
+	req := llm.NarrationRequest{
+		Scene:   s.Kind,
+		Paths:   s.Paths,
+		Recap:   c.RunRecap(),
+		Level:   c.Level(),
+		Persona: c.Persona,
+	}
+	if s.Enemy != nil {
+		req.Enemy = s.Enemy.Name
+		req.Location = s.Enemy.Location
+		req.Description = s.Enemy.Description
+	}
+	return req
+}
+
+// RunRecap summarizes the run so far for the narrator: enemies defeated
+// and the outcomes of the last few turns
+func (c *Context) RunRecap() string {
+	if c.Run == nil || len(c.Run.Turns) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Enemies defeated so far: %d.", c.Run.EnemiesDefeated)
+
+	turns := c.Run.Turns[max(0, len(c.Run.Turns)-recapTurns):]
+	for _, t := range turns {
+		if t.Outcome == "" {
+			continue
+		}
+		outcome := t.Outcome
+		if r := []rune(outcome); len(r) > recapOutcomeChars {
+			outcome = string(r[:recapOutcomeChars]) + "..."
+		}
+		if t.Enemy != "" {
+			fmt.Fprintf(&sb, "\n- Against the %s: %s", t.Enemy, outcome)
+		} else {
+			fmt.Fprintf(&sb, "\n- %s", outcome)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,172 @@
+package game
+
+import (
+	"math"
+
+	"github.com/erwaen/type-glish/internal/cefr"
+	"github.com/erwaen/type-glish/internal/llm"
+)
+
+// ScaleEnemy adjusts the enemy HP to the difficulty level
+func ScaleEnemy(e *Enemy, level cefr.Level) {
+	hp := int(math.Round(float64(e.MaxHP) * level.Profile().EnemyHPScale))
+	if hp < 1 {
+		hp = 1
+	}
+	e.MaxHP = hp
+	e.HP = hp
+}
+
+// ScaleCounterDamage adjusts the enemy counter-attack to the difficulty level
+func ScaleCounterDamage(damage int, level cefr.Level) int {
+	if damage <= 0 {
+		return 0
+	}
+	scaled := int(math.Round(float64(damage) * level.Profile().CounterScale))
+	if scaled < 1 {
+		scaled = 1
+	}
+	return scaled
+}
+
+// QuickStrikeMinAccuracy is the accuracy below which a quick strike misses
+const QuickStrikeMinAccuracy = 0.8
+
+// QuickStrikeDamage returns the bonus damage of a typing quick strike:
+// one point per 8 WPM, scaled by accuracy and capped at 10
+func QuickStrikeDamage(wpm, accuracy float64) int {
+	if accuracy < QuickStrikeMinAccuracy {
+		return 0
+	}
+	return min(10, int(wpm/8*accuracy))
+}
+
+// Limits that keep model output inside the game rules
+const (
+	MinScore           = 1
+	MaxScore           = 10
+	MaxIrrelevantScore = 2  // Off-topic input never scores higher than this
+	MaxPathHealing     = 20 // Most HP a path choice can restore
+	InjectionPenalty   = 5  // Extra counter damage when the DM confirms the player tried to cheat
+)
+
+// DM lines used when the player tries to give the grader orders
+const (
+	InjectionComment     = "Nice try, trickster. The Dungeon Master takes no orders from adventurers."
+	InjectionOutcome     = "Your words twist into a clumsy spell of cheating. It backfires, and the enemy strikes while you stumble."
+	InjectionPathOutcome = "You try to bargain with the road itself. The road is not impressed, and you find no rest."
+
+	InjectionAdventureOutcome = "You try to rewrite the world with a cheat. The words crumble to dust in your hands."
+)
+
+// counterRange returns the enemy counter-attack range for a grammar score
+func counterRange(score int) (lo, hi int) {
+	switch {
+	case score >= 8:
+		return 3, 5
+	case score >= 5:
+		return 6, 10
+	default:
+		return 11, 15
+	}
+}
+
+// ValidateCombatAssessment clamps every numeric field of a combat
+// assessment to the game rules, whatever the model returned. Injection
+// attempts score the minimum and deal no damage. The penalty damage only
+// applies when the DM agrees, by judging the sentence irrelevant: a
+// pattern match alone could be an honest sentence.
+func ValidateCombatAssessment(a *llm.CombatAssessment) {
+	confirmed := a.InjectionDetected && !a.IsRelevant && !a.Offline
+	if a.InjectionDetected {
+		a.IsRelevant = false
+		a.GrammarScore = MinScore
+	}
+
+	a.GrammarScore = max(MinScore, min(a.GrammarScore, MaxScore))
+	if !a.IsRelevant {
+		a.GrammarScore = min(a.GrammarScore, MaxIrrelevantScore)
+	}
+
+	a.DamageDealt = max(0, min(a.DamageDealt, a.GrammarScore*3/2))
+	lo, hi := counterRange(a.GrammarScore)
+	a.DamageReceived = max(lo, min(a.DamageReceived, hi))
+
+	if a.InjectionDetected {
+		a.DamageDealt = 0
+		if confirmed {
+			a.Penalty = InjectionPenalty
+			a.DamageReceived = hi + a.Penalty
+		}
+		a.DMComment = InjectionComment
+		a.Outcome = InjectionOutcome
+	}
+}
+
+// ValidatePathAssessment clamps the score and healing of a path
+// assessment to the game rules
+func ValidatePathAssessment(a *llm.PathAssessment) {
+	if a.InjectionDetected {
+		a.IsRelevant = false
+		a.GrammarScore = MinScore
+		a.DMComment = InjectionComment
+		a.Outcome = InjectionPathOutcome
+	}
+
+	a.GrammarScore = max(MinScore, min(a.GrammarScore, MaxScore))
+	a.Healing = max(0, min(a.Healing, a.GrammarScore*2, MaxPathHealing))
+	if !a.IsRelevant {
+		a.Healing = 0
+	}
+}
+
+// ValidateAssessment clamps the score of a Free Adventure assessment.
+// Injection attempts score the minimum.
+func ValidateAssessment(a *llm.Assessment) {
+	if a.InjectionDetected {
+		a.GrammarScore = MinScore
+		a.DMComment = InjectionComment
+		a.OutcomeDescription = InjectionAdventureOutcome
+	}
+	a.GrammarScore = max(MinScore, min(a.GrammarScore, MaxScore))
+}
+
+// ApplyCombatAssessment applies the game rules to an assessment and
+// updates player and enemy HP. The combo, status effects and the enemy's
+// ability are resolved here too. The assessment is updated in place so the result
+// screen shows the damage that was actually applied.
+func (c *Context) ApplyCombatAssessment(a *llm.CombatAssessment) {
+	ValidateCombatAssessment(a)
+	if !a.InjectionDetected {
+		if n := c.AntiFarm.Repeats(c.LastInput, c.Run); n > 0 {
+			a.Repeats = n
+			a.DamageDealt = c.AntiFarm.Reduce(a.DamageDealt, n)
+			a.DMComment = FarmComment
+		}
+	}
+	a.DamageReceived = ScaleCounterDamage(a.DamageReceived, c.Level())
+	c.Adaptive.Record(a.GrammarScore, c.AdaptiveEnabled)
+	c.LastStrike = c.applyStrike(a)
+	c.LastEffects = c.resolveEffects(a)
+
+	if c.CurrentEnemy != nil {
+		c.CurrentEnemy.HP -= a.DamageDealt
+		if c.CurrentEnemy.HP < 0 {
+			c.CurrentEnemy.HP = 0
+		}
+	}
+	c.Stats.HP -= a.DamageReceived + c.LastEffects.Poison
+	if c.Stats.HP < 0 {
+		c.Stats.HP = 0
+	}
+
+	c.CombatHistory = append(c.CombatHistory, llm.CombatTurn{
+		Action:         c.LastInput,
+		Score:          a.GrammarScore,
+		DamageDealt:    a.DamageDealt,
+		DamageReceived: a.DamageReceived,
+		Outcome:        a.Outcome,
+		ErrorCategory:  a.ErrorCategory,
+		Reply:          *a,
+	})
+}
@@ -0,0 +1,130 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is used for missing keys and unknown locales
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var embedded embed.FS
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]map[string]string{}
+	current  = DefaultLocale
+)
+
+func init() {
+	entries, err := embedded.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	for _, e := range entries {
+		data, err := embedded.ReadFile("locales/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		if err := add(strings.TrimSuffix(e.Name(), ".json"), data); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// add merges a JSON catalog into the given locale
+func add(locale string, data []byte) error {
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("failed to parse locale %s: %w", locale, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if catalogs[locale] == nil {
+		catalogs[locale] = map[string]string{}
+	}
+	for k, v := range messages {
+		catalogs[locale][k] = v
+	}
+	return nil
+}
+
+// LoadDir loads extra or overriding <locale>.json catalogs from dir.
+// A missing directory is not an error.
+func LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if err := add(strings.TrimSuffix(filepath.Base(p), ".json"), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetLocale switches the locale used by T. Unknown locales fall back to
+// DefaultLocale.
+func SetLocale(locale string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := catalogs[locale]; !ok {
+		if locale != "" {
+			log.Printf("Unknown locale %q, using %s", locale, DefaultLocale)
+		}
+		locale = DefaultLocale
+	}
+	current = locale
+}
+
+// Locale returns the current locale
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Locales returns the available locales, sorted
+func Locales() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	locales := make([]string, 0, len(catalogs))
+	for l := range catalogs {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// T returns the message for key in the current locale, formatted with
+// args if any. Missing keys fall back to DefaultLocale, then to the key.
+func T(key string, args ...any) string {
+	mu.RLock()
+	msg, ok := catalogs[current][key]
+	if !ok {
+		msg, ok = catalogs[DefaultLocale][key]
+	}
+	mu.RUnlock()
+
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
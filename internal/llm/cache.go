@@ -0,0 +1,167 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Cache defaults, used when the config leaves them at zero
+const (
+	DefaultCacheTTL      = 7 * 24 * time.Hour
+	DefaultCacheMaxBytes = 20 << 20
+)
+
+// cacheEntry is one cached reply, stored as <key>.json
+type cacheEntry struct {
+	Provider string    `json:"provider"`
+	Created  time.Time `json:"created"`
+	Response string    `json:"response"`
+}
+
+// Evicter is implemented by providers that can drop a cached reply, so a
+// reply the client could not use is not replayed until it expires
+type Evicter interface {
+	Evict(messages []ChatMessage)
+}
+
+// CachedProvider wraps a provider with a content-addressed disk cache.
+// The key covers the provider and model and every message, so the prompt
+// template, game context and player input all take part in it.
+type CachedProvider struct {
+	next     Provider
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+}
+
+// NewCachedProvider caches the replies of next in dir. Zero ttl or
+// maxBytes use the defaults.
+func NewCachedProvider(next Provider, dir string, ttl time.Duration, maxBytes int64) *CachedProvider {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultCacheMaxBytes
+	}
+	return &CachedProvider{next: next, dir: dir, ttl: ttl, maxBytes: maxBytes}
+}
+
+func (p *CachedProvider) Name() string {
+	return p.next.Name()
+}
+
+// Call returns the cached reply if there is a fresh one, otherwise calls
+// the wrapped provider and stores its reply. Requests marked NoCache
+// bypass the cache.
+func (p *CachedProvider) Call(messages []ChatMessage) (string, error) {
+	if slices.ContainsFunc(messages, func(m ChatMessage) bool { return m.NoCache }) {
+		return p.next.Call(messages)
+	}
+
+	key := p.key(messages)
+	path := filepath.Join(p.dir, key+".json")
+
+	if data, err := os.ReadFile(path); err == nil {
+		var entry cacheEntry
+		if err := json.Unmarshal(data, &entry); err == nil && time.Since(entry.Created) < p.ttl {
+			log.Printf("llm cache hit %s (%s)", key[:12], entry.Provider)
+			return entry.Response, nil
+		}
+	}
+
+	resp, err := p.next.Call(messages)
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.store(path, resp); err != nil {
+		log.Printf("Error writing llm cache: %v", err)
+	}
+	return resp, nil
+}
+
+// Evict removes the cached reply of a request
+func (p *CachedProvider) Evict(messages []ChatMessage) {
+	key := p.key(messages)
+	if err := os.Remove(filepath.Join(p.dir, key+".json")); err == nil {
+		log.Printf("llm cache evicted %s", key[:12])
+	}
+}
+
+// key hashes the provider name and the messages
+func (p *CachedProvider) key(messages []ChatMessage) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", p.next.Name())
+	for _, m := range messages {
+		fmt.Fprintf(h, "%s\n%d\n%s\n", m.Role, len(m.Content), m.Content)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (p *CachedProvider) store(path, resp string) error {
+	if err := os.MkdirAll(p.dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cacheEntry{Provider: p.next.Name(), Created: time.Now(), Response: resp})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	return p.prune()
+}
+
+// prune removes expired entries, then the oldest ones until the cache
+// fits in maxBytes
+func (p *CachedProvider) prune() error {
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return err
+	}
+
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(p.dir, e.Name())
+		if time.Since(info.ModTime()) >= p.ttl {
+			os.Remove(path)
+			continue
+		}
+		files = append(files, file{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	slices.SortFunc(files, func(a, b file) int {
+		return a.modTime.Compare(b.modTime)
+	})
+	for _, f := range files {
+		if total <= p.maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err == nil {
+			total -= f.size
+		}
+	}
+	return nil
+}
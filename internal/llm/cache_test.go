@@ -0,0 +1,228 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/erwaen/type-glish/internal/cefr"
+)
+
+// fakeProvider answers with its replies in order, repeating the last one,
+// and counts its calls
+type fakeProvider struct {
+	name    string
+	replies []string
+	err     error
+	calls   int
+}
+
+func (f *fakeProvider) Name() string {
+	if f.name == "" {
+		return "fake"
+	}
+	return f.name
+}
+
+func (f *fakeProvider) Call(messages []ChatMessage) (string, error) {
+	f.calls++
+	if f.err != nil {
+		return "", f.err
+	}
+	if len(f.replies) == 0 {
+		return "", nil
+	}
+	return f.replies[min(f.calls, len(f.replies))-1], nil
+}
+
+func cacheFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func request(content string) []ChatMessage {
+	return []ChatMessage{
+		{Role: "system", Content: "You are the DM."},
+		{Role: "user", Content: content},
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	a := NewCachedProvider(&fakeProvider{name: "gemini/flash"}, t.TempDir(), 0, 0)
+	b := NewCachedProvider(&fakeProvider{name: "llamacpp/qwen"}, t.TempDir(), 0, 0)
+
+	base := a.key(request("I attack."))
+	tests := []struct {
+		name string
+		key  string
+		same bool
+	}{
+		{"same request", a.key(request("I attack.")), true},
+		{"NoCache does not change the key", a.key([]ChatMessage{{Role: "system", Content: "You are the DM."}, {Role: "user", Content: "I attack.", NoCache: true}}), true},
+		{"other input", a.key(request("I defend.")), false},
+		{"other provider", b.key(request("I attack.")), false},
+		{"other role", a.key([]ChatMessage{{Role: "system", Content: "You are the DM."}, {Role: "assistant", Content: "I attack."}}), false},
+		{"content moved between messages", a.key([]ChatMessage{{Role: "system", Content: "You are the DM.\nuser"}, {Role: "user", Content: "I attack."}}), false},
+		{"extra message", a.key(append(request("I attack."), ChatMessage{Role: "user", Content: ""})), false},
+	}
+	for _, tt := range tests {
+		if got := tt.key == base; got != tt.same {
+			t.Errorf("%s: same key = %v, want %v", tt.name, got, tt.same)
+		}
+	}
+}
+
+func TestCachedProviderHit(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeProvider{replies: []string{"first", "second"}}
+	p := NewCachedProvider(fake, dir, 0, 0)
+
+	for i, want := range []string{"first", "first"} {
+		got, err := p.Call(request("I attack."))
+		if err != nil || got != want {
+			t.Fatalf("call %d = %q, %v, want %q", i+1, got, err, want)
+		}
+	}
+	if fake.calls != 1 {
+		t.Errorf("provider called %d times, want 1", fake.calls)
+	}
+	if got, _ := p.Call(request("I defend.")); got != "second" {
+		t.Errorf("other request = %q, want %q", got, "second")
+	}
+	if n := len(cacheFiles(t, dir)); n != 2 {
+		t.Errorf("%d cache files, want 2", n)
+	}
+}
+
+func TestCachedProviderErrorsAreNotCached(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeProvider{err: errors.New("down")}
+	p := NewCachedProvider(fake, dir, 0, 0)
+
+	if _, err := p.Call(request("I attack.")); err == nil {
+		t.Fatal("Call succeeded, want the provider error")
+	}
+	if n := len(cacheFiles(t, dir)); n != 0 {
+		t.Errorf("%d cache files, want 0", n)
+	}
+}
+
+func TestCachedProviderTTL(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeProvider{replies: []string{"old", "new"}}
+	p := NewCachedProvider(fake, dir, time.Hour, 0)
+	p.Call(request("I attack."))
+
+	// Age the entry past the TTL
+	path := filepath.Join(dir, p.key(request("I attack."))+".json")
+	entry := cacheEntry{Provider: fake.Name(), Created: time.Now().Add(-2 * time.Hour), Response: "old"}
+	data, _ := json.Marshal(entry)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := p.Call(request("I attack.")); got != "new" {
+		t.Errorf("expired entry answered %q, want %q", got, "new")
+	}
+	if fake.calls != 2 {
+		t.Errorf("provider called %d times, want 2", fake.calls)
+	}
+}
+
+func TestCachedProviderPrune(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeProvider{replies: []string{"reply"}}
+	p := NewCachedProvider(fake, dir, time.Hour, 0)
+
+	p.Call(request("oldest"))
+	p.Call(request("older"))
+	entry, err := os.Stat(cacheFiles(t, dir)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := entry.Size()
+
+	// Make the order of the entries explicit, and expire a third one
+	now := time.Now()
+	os.Chtimes(filepath.Join(dir, p.key(request("oldest"))+".json"), now, now.Add(-3*time.Minute))
+	os.Chtimes(filepath.Join(dir, p.key(request("older"))+".json"), now, now.Add(-2*time.Minute))
+	p.Call(request("expired"))
+	os.Chtimes(filepath.Join(dir, p.key(request("expired"))+".json"), now, now.Add(-2*time.Hour))
+
+	// Room for two and a half entries: the expired one and the oldest one go
+	p.maxBytes = 2*size + size/2
+	p.Call(request("newest"))
+
+	for name, want := range map[string]bool{"oldest": false, "older": true, "expired": false, "newest": true} {
+		_, err := os.Stat(filepath.Join(dir, p.key(request(name))+".json"))
+		if got := err == nil; got != want {
+			t.Errorf("entry %q kept = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestCachedProviderNoCache(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeProvider{replies: []string{"first", "second"}}
+	p := NewCachedProvider(fake, dir, 0, 0)
+
+	messages := []ChatMessage{{Role: "user", Content: "Invent 3 new enemies.", NoCache: true}}
+	p.Call(messages)
+	if got, _ := p.Call(messages); got != "second" {
+		t.Errorf("NoCache request = %q, want a fresh reply", got)
+	}
+	if n := len(cacheFiles(t, dir)); n != 0 {
+		t.Errorf("%d cache files, want 0", n)
+	}
+}
+
+func TestCachedProviderEvict(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeProvider{replies: []string{"first", "second"}}
+	p := NewCachedProvider(fake, dir, 0, 0)
+
+	p.Call(request("I attack."))
+	p.Evict(request("I defend."))
+	if n := len(cacheFiles(t, dir)); n != 1 {
+		t.Fatalf("evicting another request left %d cache files, want 1", n)
+	}
+	p.Evict(request("I attack."))
+	if got, _ := p.Call(request("I attack.")); got != "second" {
+		t.Errorf("evicted request = %q, want a fresh reply", got)
+	}
+}
+
+func TestClientEvictsUnparseableReplies(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	dir := t.TempDir()
+	fake := &fakeProvider{replies: []string{
+		"Sorry, I can't grade that.",
+		`{"corrected": "I open the door.", "score": 8, "dm_comment": "Nice.", "outcome": "The door creaks open."}`,
+	}}
+	c := NewClient(Chain(fake, WithMetrics(NewMetrics()), WithCache(dir, 0, 0)))
+	req := ActionRequest{Action: "I open the door.", Level: cefr.DefaultLevel}
+
+	if msg := c.AnalyzeAction(req).(AssessmentMsg); !errors.Is(msg.Err, ErrInvalidJSON) {
+		t.Fatalf("first reply error = %v, want ErrInvalidJSON", msg.Err)
+	}
+	if n := len(cacheFiles(t, dir)); n != 0 {
+		t.Fatalf("unparseable reply left %d cache files, want 0", n)
+	}
+
+	msg := c.AnalyzeAction(req).(AssessmentMsg)
+	if msg.Err != nil || msg.Data.GrammarScore != 8 {
+		t.Fatalf("second reply = %+v, %v, want a fresh graded reply", msg.Data, msg.Err)
+	}
+	if c.AnalyzeAction(req); fake.calls != 2 {
+		t.Errorf("provider called %d times, want 2 with the good reply cached", fake.calls)
+	}
+}
@@ -0,0 +1,129 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/erwaen/type-glish/internal/config"
+)
+
+const calibrationFileName = "calibration.json"
+
+// minCalibrationSlope rejects fits that would flatten or invert the scale
+const minCalibrationSlope = 0.2
+
+// Calibration maps the raw scores of one provider and model onto the
+// common scale of the eval corpus with a linear fit: score*Scale + Offset
+type Calibration struct {
+	Model   string    `json:"model"`
+	Scale   float64   `json:"scale"`
+	Offset  float64   `json:"offset"`
+	Anchors int       `json:"anchors"` // Sentences the fit is based on
+	Created time.Time `json:"created"`
+}
+
+// IsZero reports whether the calibration is unset, in which case scores
+// are used as they are
+func (c Calibration) IsZero() bool {
+	return c.Scale == 0
+}
+
+// Apply maps a raw 1-10 score onto the common scale
+func (c Calibration) Apply(score int) int {
+	if c.IsZero() {
+		return score
+	}
+	calibrated := int(math.Round(float64(score)*c.Scale + c.Offset))
+	return max(1, min(10, calibrated))
+}
+
+// rescale scales a value the model derived from its raw score, like
+// damage or healing, by how much calibration moved the score. Values of a
+// raw score of 0 are left as they are.
+func rescale(value, raw, calibrated int) int {
+	if raw <= 0 {
+		return value
+	}
+	return int(math.Round(float64(value) * float64(calibrated) / float64(raw)))
+}
+
+// FitCalibration fits raw provider scores to the expected scores of the
+// same anchor sentences with least squares
+func FitCalibration(model string, raw, expected []float64) (Calibration, error) {
+	if len(raw) != len(expected) {
+		return Calibration{}, fmt.Errorf("calibration needs one expected score per raw score")
+	}
+	if len(raw) < 3 {
+		return Calibration{}, fmt.Errorf("calibration needs at least 3 graded anchors, got %d", len(raw))
+	}
+
+	n := float64(len(raw))
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range raw {
+		sumX += raw[i]
+		sumY += expected[i]
+		sumXY += raw[i] * expected[i]
+		sumXX += raw[i] * raw[i]
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return Calibration{}, fmt.Errorf("the model gave every anchor the same score, nothing to calibrate")
+	}
+
+	scale := (n*sumXY - sumX*sumY) / denom
+	if scale < minCalibrationSlope {
+		return Calibration{}, fmt.Errorf("the model's scores don't follow the anchors (slope %.2f)", scale)
+	}
+	return Calibration{
+		Model:   model,
+		Scale:   scale,
+		Offset:  (sumY - scale*sumX) / n,
+		Anchors: len(raw),
+		Created: time.Now(),
+	}, nil
+}
+
+// LoadCalibrations reads the stored calibrations, keyed by provider name
+func LoadCalibrations() (map[string]Calibration, error) {
+	path, err := config.GetDataPath(calibrationFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]Calibration{}, nil
+		}
+		return nil, err
+	}
+
+	calibrations := map[string]Calibration{}
+	if err := json.Unmarshal(data, &calibrations); err != nil {
+		return nil, fmt.Errorf("failed to parse calibrations: %w", err)
+	}
+	return calibrations, nil
+}
+
+// SaveCalibration stores the calibration of its model, replacing any
+// previous one
+func SaveCalibration(c Calibration) error {
+	calibrations, err := LoadCalibrations()
+	if err != nil {
+		return err
+	}
+	calibrations[c.Model] = c
+
+	path, err := config.GetDataPath(calibrationFileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(calibrations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal calibrations: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
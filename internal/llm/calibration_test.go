@@ -0,0 +1,134 @@
+package llm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFitCalibration(t *testing.T) {
+	tests := []struct {
+		name       string
+		raw        []float64
+		expected   []float64
+		wantScale  float64
+		wantOffset float64
+		wantErr    bool
+	}{
+		{
+			name:      "already calibrated",
+			raw:       []float64{2, 5, 8},
+			expected:  []float64{2, 5, 8},
+			wantScale: 1,
+		},
+		{
+			name:       "generous grader",
+			raw:        []float64{4, 6, 8, 10},
+			expected:   []float64{2, 4, 6, 8},
+			wantScale:  1,
+			wantOffset: -2,
+		},
+		{
+			name:       "grader that squeezes the scale",
+			raw:        []float64{4, 5, 6, 7},
+			expected:   []float64{1, 3, 5, 7},
+			wantScale:  2,
+			wantOffset: -7,
+		},
+		{
+			name:       "noisy grader",
+			raw:        []float64{3, 5, 7},
+			expected:   []float64{2, 6, 7},
+			wantScale:  1.25,
+			wantOffset: -1.25,
+		},
+		{
+			name:     "too few anchors",
+			raw:      []float64{3, 8},
+			expected: []float64{3, 8},
+			wantErr:  true,
+		},
+		{
+			name:     "mismatched lengths",
+			raw:      []float64{3, 5, 8},
+			expected: []float64{3, 5},
+			wantErr:  true,
+		},
+		{
+			name:     "every raw score the same",
+			raw:      []float64{7, 7, 7, 7},
+			expected: []float64{2, 4, 6, 8},
+			wantErr:  true,
+		},
+		{
+			name:     "inverted scale",
+			raw:      []float64{2, 5, 8},
+			expected: []float64{8, 5, 2},
+			wantErr:  true,
+		},
+		{
+			name:     "flat scale",
+			raw:      []float64{2, 5, 8},
+			expected: []float64{5, 5, 6},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal, err := FitCalibration("fake", tt.raw, tt.expected)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("FitCalibration = %+v, want an error", cal)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(cal.Scale-tt.wantScale) > 1e-9 || math.Abs(cal.Offset-tt.wantOffset) > 1e-9 {
+				t.Errorf("fit = %.3f*x%+.3f, want %.3f*x%+.3f", cal.Scale, cal.Offset, tt.wantScale, tt.wantOffset)
+			}
+			if cal.Model != "fake" || cal.Anchors != len(tt.raw) || cal.Created.IsZero() {
+				t.Errorf("Calibration = %+v, want model, anchors and time filled in", cal)
+			}
+		})
+	}
+}
+
+func TestCalibrationApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		cal   Calibration
+		score int
+		want  int
+	}{
+		{"zero calibration", Calibration{}, 7, 7},
+		{"shift down", Calibration{Scale: 1, Offset: -2}, 7, 5},
+		{"rounds to nearest", Calibration{Scale: 1.25, Offset: -1.25}, 6, 6},
+		{"rounds half up", Calibration{Scale: 1.25, Offset: -1.25}, 7, 8},
+		{"clamped to 10", Calibration{Scale: 2, Offset: -7}, 10, 10},
+		{"clamped to 1", Calibration{Scale: 2, Offset: -7}, 3, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.cal.Apply(tt.score); got != tt.want {
+			t.Errorf("%s: Apply(%d) = %d, want %d", tt.name, tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestRescale(t *testing.T) {
+	tests := []struct {
+		value, raw, calibrated, want int
+	}{
+		{12, 8, 8, 12},
+		{12, 8, 6, 9},
+		{9, 6, 8, 12},
+		{5, 8, 6, 4},
+		{0, 8, 6, 0},
+		{3, 0, 4, 3},
+	}
+	for _, tt := range tests {
+		if got := rescale(tt.value, tt.raw, tt.calibrated); got != tt.want {
+			t.Errorf("rescale(%d, %d, %d) = %d, want %d", tt.value, tt.raw, tt.calibrated, got, tt.want)
+		}
+	}
+}
@@ -2,23 +2,38 @@ package llm
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erwaen/type-glish/internal/cefr"
 )
 
+// ErrInvalidJSON is wrapped by errors caused by a model reply that is not
+// the JSON the prompt asked for
+var ErrInvalidJSON = errors.New("invalid JSON from model")
+
 // ChatMessage represents a single message in the conversation history
 type ChatMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+
+	NoCache bool `json:"-"` // Never cache the reply to a request with this message
 }
 
+// Assessment is the LLM response for a Free Adventure action
 type Assessment struct {
 	CorrectedSentence  string `json:"corrected"`
 	GrammarScore       int    `json:"score"`
-	DamageDealt        int    `json:"damage"`
 	DMComment          string `json:"dm_comment"`
 	OutcomeDescription string `json:"outcome"`
+
+	InjectionDetected bool   `json:"-"` // Set by the client, never by the model
+	Offline           bool   `json:"-"` // Graded by the rule-based offline grader
+	RawScore          int    `json:"-"` // Score before calibration
+	Prompt            string `json:"-"` // Tag of the prompt template, e.g. "critic@3"
 }
 
 type AssessmentMsg struct {
@@ -35,6 +50,17 @@ type CombatAssessment struct {
 	DMComment         string `json:"dm_comment"`
 	Outcome           string `json:"outcome"`
 	IsRelevant        bool   `json:"is_relevant"`
+
+	ErrorCategory string   `json:"error_category"` // Main type of mistake, "none" if perfect
+	Vocabulary    []string `json:"vocabulary"`     // Words worth learning from the corrected sentence
+	Explanation   string   `json:"explanation"`    // Grammar explanation in the learner's native language
+
+	InjectionDetected bool   `json:"-"` // Set by the client, never by the model
+	Penalty           int    `json:"-"` // Extra counter damage for a confirmed injection, set by the rules
+	Repeats           int    `json:"-"` // Earlier sentences of the run this one repeats, set by the rules
+	Offline           bool   `json:"-"` // Graded by the rule-based offline grader
+	RawScore          int    `json:"-"` // Score before calibration
+	Prompt            string `json:"-"` // Tag of the prompt template, e.g. "combat@2"
 }
 
 type CombatAssessmentMsg struct {
@@ -50,6 +76,12 @@ type PathAssessment struct {
 	DMComment         string `json:"dm_comment"`
 	Outcome           string `json:"outcome"`
 	IsRelevant        bool   `json:"is_relevant"`
+	Explanation       string `json:"explanation"` // Grammar explanation in the learner's native language
+
+	InjectionDetected bool   `json:"-"` // Set by the client, never by the model
+	Offline           bool   `json:"-"` // Graded by the rule-based offline grader
+	RawScore          int    `json:"-"` // Score before calibration
+	Prompt            string `json:"-"` // Tag of the prompt template, e.g. "path@1"
 }
 
 type PathAssessmentMsg struct {
@@ -57,8 +89,50 @@ type PathAssessmentMsg struct {
 	Err  error
 }
 
+// CombatRequest holds everything the DM needs to judge a combat action
+type CombatRequest struct {
+	Action   string
+	Enemy    string
+	Location string
+	Tier     int    // Enemy tier, 0 if unknown
+	Focus    string // Grammar area the enemy embodies, may be empty
+	Level    cefr.Level
+	Persona  string // DM persona ID, empty for the default
+
+	ExplanationLanguage string // Empty when no native explanation is wanted
+
+	// Current state of the fight, so the DM can narrate it coherently
+	PlayerHP    int
+	PlayerMaxHP int
+	EnemyHP     int
+	EnemyMaxHP  int
+	History     []CombatTurn // Previous turns against this enemy, oldest first
+}
+
+// ActionRequest holds everything the DM needs to judge a Free Adventure
+// action
+type ActionRequest struct {
+	Action  string
+	Level   cefr.Level
+	Persona string // DM persona ID, empty for the default
+	Recap   string // Outcomes of the last actions, oldest first
+}
+
+// PathRequest holds everything the DM needs to judge a path choice
+type PathRequest struct {
+	Choice  string
+	Options string
+	Level   cefr.Level
+	Persona string // DM persona ID, empty for the default
+
+	ExplanationLanguage string // Empty when no native explanation is wanted
+}
+
 type Client struct {
 	provider Provider
+
+	calibrationsOnce sync.Once
+	calibrations     map[string]Calibration // By provider name, loaded once
 }
 
 func NewClient(p Provider) *Client {
@@ -67,12 +141,61 @@ func NewClient(p Provider) *Client {
 	}
 }
 
-func (c *Client) AnalyzeAction(userAction string) tea.Msg {
+// discard drops the cached reply to a request the client could not parse
+func (c *Client) discard(messages []ChatMessage) {
+	if e, ok := c.provider.(Evicter); ok {
+		e.Evict(messages)
+	}
+}
+
+// ProviderName identifies the backend and model behind the client
+func (c *Client) ProviderName() string {
+	return c.provider.Name()
+}
+
+// Calibration returns the stored score calibration of the provider that
+// currently answers, or a zero Calibration if it was never calibrated
+func (c *Client) Calibration() Calibration {
+	return c.calibrationFor(c.provider.Name())
+}
+
+// calibrationFor returns the stored calibration of the named provider. The
+// name is looked up on every call because it can change during a session,
+// for example when the daily budget switches Gemini to llama.cpp. Graders
+// read the name before calling, since the call itself can spend the budget.
+func (c *Client) calibrationFor(name string) Calibration {
+	c.calibrationsOnce.Do(func() {
+		calibrations, err := LoadCalibrations()
+		if err != nil {
+			log.Printf("Error loading calibrations: %v", err)
+			return
+		}
+		c.calibrations = calibrations
+	})
+	return c.calibrations[name]
+}
+
+// AnalyzeAction grades a Free Adventure action and narrates its outcome
+func (c *Client) AnalyzeAction(req ActionRequest) tea.Msg {
+	profile := req.Level.Profile()
+	prompt, tmpl, err := renderPrompt(CriticPromptName, req.Persona, PromptData{
+		Difficulty: req.Level,
+		Strictness: profile.Strictness,
+		Structures: profile.Structures,
+		Vocabulary: profile.Vocabulary,
+		Recap:      req.Recap,
+	})
+	if err != nil {
+		return AssessmentMsg{Err: err}
+	}
+
+	action := SanitizeInput(req.Action)
 	messages := []ChatMessage{
-		{Role: "system", Content: CriticPrompt},
-		{Role: "user", Content: userAction},
+		{Role: "system", Content: prompt},
+		{Role: "user", Content: wrapPlayerText(action)},
 	}
 
+	provider := c.provider.Name()
 	resp, err := c.provider.Call(messages)
 	if err != nil {
 		return AssessmentMsg{Err: err}
@@ -81,21 +204,30 @@ func (c *Client) AnalyzeAction(userAction string) tea.Msg {
 	var assessment Assessment
 	err = json.Unmarshal([]byte(resp), &assessment)
 	if err != nil {
-		return AssessmentMsg{Err: fmt.Errorf("failed to parse JSON: %w", err)}
+		c.discard(messages)
+		return AssessmentMsg{Err: fmt.Errorf("failed to parse JSON: %w: %w", ErrInvalidJSON, err)}
 	}
+	assessment.InjectionDetected = DetectInjection(action)
+	assessment.Prompt = tmpl.Tag()
+
+	assessment.RawScore = assessment.GrammarScore
+	if cal := c.calibrationFor(provider); !cal.IsZero() {
+		assessment.GrammarScore = cal.Apply(assessment.RawScore)
+	}
+	log.Printf("action assessment: prompt %s, score %d (raw %d)", assessment.Prompt, assessment.GrammarScore, assessment.RawScore)
 
 	return AssessmentMsg{Data: assessment}
 }
 
 // AnalyzeCombatAction analyzes a combat action with enemy context
-func (c *Client) AnalyzeCombatAction(userAction, enemyName, location string) tea.Msg {
-	prompt := fmt.Sprintf(CombatPromptTemplate, enemyName, location)
-
-	messages := []ChatMessage{
-		{Role: "system", Content: prompt},
-		{Role: "user", Content: userAction},
+func (c *Client) AnalyzeCombatAction(req CombatRequest) tea.Msg {
+	req.Action = SanitizeInput(req.Action)
+	messages, prompt, err := buildCombatMessages(req)
+	if err != nil {
+		return CombatAssessmentMsg{Err: err}
 	}
 
+	provider := c.provider.Name()
 	resp, err := c.provider.Call(messages)
 	if err != nil {
 		return CombatAssessmentMsg{Err: err}
@@ -104,21 +236,45 @@ func (c *Client) AnalyzeCombatAction(userAction, enemyName, location string) tea
 	var assessment CombatAssessment
 	err = json.Unmarshal([]byte(resp), &assessment)
 	if err != nil {
-		return CombatAssessmentMsg{Err: fmt.Errorf("failed to parse combat JSON: %w", err)}
+		c.discard(messages)
+		return CombatAssessmentMsg{Err: fmt.Errorf("failed to parse combat JSON: %w: %w", ErrInvalidJSON, err)}
+	}
+	assessment.InjectionDetected = DetectInjection(req.Action)
+	assessment.Prompt = prompt.Tag()
+
+	// Map the score onto the common scale before the rules engine sees it
+	assessment.RawScore = assessment.GrammarScore
+	if cal := c.calibrationFor(provider); !cal.IsZero() {
+		assessment.GrammarScore = cal.Apply(assessment.RawScore)
+		assessment.DamageDealt = rescale(assessment.DamageDealt, assessment.RawScore, assessment.GrammarScore)
 	}
+	log.Printf("combat assessment: prompt %s, score %d (raw %d)", assessment.Prompt, assessment.GrammarScore, assessment.RawScore)
 
 	return CombatAssessmentMsg{Data: assessment}
 }
 
 // AnalyzePathChoice analyzes a path choice for healing
-func (c *Client) AnalyzePathChoice(userChoice, pathOptions string) tea.Msg {
-	prompt := fmt.Sprintf(PathChoicePromptTemplate, pathOptions)
+func (c *Client) AnalyzePathChoice(req PathRequest) tea.Msg {
+	profile := req.Level.Profile()
+	prompt, tmpl, err := renderPrompt(PathPromptName, req.Persona, PromptData{
+		Options:         req.Options,
+		Difficulty:      req.Level,
+		Strictness:      profile.Strictness,
+		Structures:      profile.Structures,
+		Vocabulary:      profile.Vocabulary,
+		ExplanationRule: explanationRule(req.ExplanationLanguage),
+	})
+	if err != nil {
+		return PathAssessmentMsg{Err: err}
+	}
 
+	choice := SanitizeInput(req.Choice)
 	messages := []ChatMessage{
 		{Role: "system", Content: prompt},
-		{Role: "user", Content: userChoice},
+		{Role: "user", Content: wrapPlayerText(choice)},
 	}
 
+	provider := c.provider.Name()
 	resp, err := c.provider.Call(messages)
 	if err != nil {
 		return PathAssessmentMsg{Err: err}
@@ -127,8 +283,27 @@ func (c *Client) AnalyzePathChoice(userChoice, pathOptions string) tea.Msg {
 	var assessment PathAssessment
 	err = json.Unmarshal([]byte(resp), &assessment)
 	if err != nil {
-		return PathAssessmentMsg{Err: fmt.Errorf("failed to parse path JSON: %w", err)}
+		c.discard(messages)
+		return PathAssessmentMsg{Err: fmt.Errorf("failed to parse path JSON: %w: %w", ErrInvalidJSON, err)}
 	}
+	assessment.InjectionDetected = DetectInjection(choice)
+	assessment.Prompt = tmpl.Tag()
+
+	assessment.RawScore = assessment.GrammarScore
+	if cal := c.calibrationFor(provider); !cal.IsZero() {
+		assessment.GrammarScore = cal.Apply(assessment.RawScore)
+		assessment.Healing = rescale(assessment.Healing, assessment.RawScore, assessment.GrammarScore)
+	}
+	log.Printf("path assessment: prompt %s, score %d (raw %d)", assessment.Prompt, assessment.GrammarScore, assessment.RawScore)
 
 	return PathAssessmentMsg{Data: assessment}
 }
+
+// explanationRule tells the model whether and in which language to
+// explain the grammar mistake
+func explanationRule(language string) string {
+	if language == "" {
+		return "explanation must be an empty string."
+	}
+	return fmt.Sprintf(ExplanationRuleTemplate, language, language)
+}
@@ -0,0 +1,104 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+)
+
+// Limits checked by ValidateEnemyDraft
+const (
+	maxEnemyName        = 30
+	maxEnemyDescription = 160
+	maxEnemyLocation    = 40
+)
+
+// GrammarFocuses are the grammar areas an enemy can embody
+var GrammarFocuses = []string{
+	"verb tense", "agreement", "articles", "prepositions", "word order", "plurals",
+	"comparatives", "questions", "conjunctions", "punctuation", "spelling", "word choice",
+}
+
+// EnemyDraft is an enemy invented by the model, before the game validates
+// and balances it
+type EnemyDraft struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Location     string `json:"location"`
+	Tier         int    `json:"tier"`
+	GrammarFocus string `json:"grammar_focus"`
+}
+
+type enemyDrafts struct {
+	Enemies []EnemyDraft `json:"enemies"`
+}
+
+// ValidateEnemyDraft checks a draft against the schema the prompt asks
+// for, after trimming its fields
+func ValidateEnemyDraft(d *EnemyDraft) error {
+	d.Name = strings.Join(strings.Fields(d.Name), " ")
+	d.Description = strings.Join(strings.Fields(d.Description), " ")
+	d.Location = strings.Join(strings.Fields(d.Location), " ")
+	d.GrammarFocus = strings.ToLower(strings.TrimSpace(d.GrammarFocus))
+
+	switch {
+	case d.Name == "" || len([]rune(d.Name)) > maxEnemyName:
+		return fmt.Errorf("name %q must be 1-%d characters", d.Name, maxEnemyName)
+	case d.Description == "" || len([]rune(d.Description)) > maxEnemyDescription:
+		return fmt.Errorf("description of %s must be 1-%d characters", d.Name, maxEnemyDescription)
+	case d.Location == "" || len([]rune(d.Location)) > maxEnemyLocation:
+		return fmt.Errorf("location of %s must be 1-%d characters", d.Name, maxEnemyLocation)
+	case d.Tier < 1 || d.Tier > 4:
+		return fmt.Errorf("tier of %s must be 1-4, got %d", d.Name, d.Tier)
+	case !slices.Contains(GrammarFocuses, d.GrammarFocus):
+		return fmt.Errorf("unknown grammar focus %q for %s", d.GrammarFocus, d.Name)
+	case DetectInjection(d.Name + " " + d.Description + " " + d.Location):
+		return fmt.Errorf("draft %s looks like instructions, not an enemy", d.Name)
+	}
+	return nil
+}
+
+// GenerateEnemies asks the model for count new enemies that differ from
+// the existing ones. Drafts that fail validation are dropped, so fewer
+// than count may be returned. Generation never goes through the response
+// cache: a cached reply would hand back the same drafts, or the same
+// broken JSON, every time the bestiary tries to grow.
+func (c *Client) GenerateEnemies(count int, existing []string, persona string) ([]EnemyDraft, error) {
+	prompt, tmpl, err := renderPrompt(BestiaryPromptName, persona, PromptData{
+		Count:          count,
+		Existing:       strings.Join(existing, ", "),
+		GrammarFocuses: strings.Join(GrammarFocuses, ", "),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.provider.Call([]ChatMessage{
+		{Role: "system", Content: prompt},
+		{Role: "user", Content: fmt.Sprintf("Invent %d new enemies.", count), NoCache: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var out enemyDrafts
+	dec := json.NewDecoder(bytes.NewReader([]byte(resp)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to parse enemies JSON: %w: %w", ErrInvalidJSON, err)
+	}
+
+	var valid []EnemyDraft
+	for _, d := range out.Enemies {
+		if err := ValidateEnemyDraft(&d); err != nil {
+			log.Printf("Dropping generated enemy: %v", err)
+			continue
+		}
+		valid = append(valid, d)
+	}
+	log.Printf("enemy generation: prompt %s, %d of %d drafts valid", tmpl.Tag(), len(valid), len(out.Enemies))
+	return valid, nil
+}
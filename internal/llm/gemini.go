@@ -2,8 +2,11 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"google.golang.org/genai"
 )
@@ -13,6 +16,9 @@ const DefaultGeminiModel = "gemini-3-flash-preview"
 type GeminiProvider struct {
 	client *genai.Client
 	model  string
+
+	mu        sync.Mutex
+	lastUsage Usage
 }
 
 func NewGeminiProvider(ctx context.Context, apiKey string, modelName string) (*GeminiProvider, error) {
@@ -34,6 +40,11 @@ func NewGeminiProvider(ctx context.Context, apiKey string, modelName string) (*G
 	}, nil
 }
 
+// Name returns "gemini/<model>"
+func (p *GeminiProvider) Name() string {
+	return "gemini/" + p.model
+}
+
 func (p *GeminiProvider) Call(messages []ChatMessage) (string, error) {
 	ctx := context.Background()
 
@@ -165,9 +176,22 @@ func (p *GeminiProvider) Call(messages []ChatMessage) (string, error) {
 
 	resp, err := p.client.Models.GenerateContent(ctx, p.model, allContents, req)
 	if err != nil {
+		var apiErr genai.APIError
+		if errors.As(err, &apiErr) {
+			err = geminiStatusError(apiErr)
+		}
 		return "", fmt.Errorf("failed to generate content: %w", err)
 	}
 
+	usage := Usage{Calls: 1}
+	if md := resp.UsageMetadata; md != nil {
+		usage.PromptTokens = int(md.PromptTokenCount)
+		usage.CompletionTokens = int(md.CandidatesTokenCount + md.ThoughtsTokenCount)
+	}
+	p.mu.Lock()
+	p.lastUsage = usage
+	p.mu.Unlock()
+
 	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
 		return "", fmt.Errorf("no content returned")
 	}
@@ -180,6 +204,30 @@ func (p *GeminiProvider) Call(messages []ChatMessage) (string, error) {
 	return sb.String(), nil
 }
 
+// geminiStatusError converts a Gemini API error, reading the retry delay
+// Gemini sends in a RetryInfo detail instead of a Retry-After header
+func geminiStatusError(e genai.APIError) *StatusError {
+	se := &StatusError{StatusCode: e.Code, Message: e.Message}
+	for _, d := range e.Details {
+		if d["@type"] != "type.googleapis.com/google.rpc.RetryInfo" {
+			continue
+		}
+		if delay, ok := d["retryDelay"].(string); ok {
+			if dur, err := time.ParseDuration(delay); err == nil {
+				se.RetryAfter = dur
+			}
+		}
+	}
+	return se
+}
+
+// LastUsage returns the token usage reported by Gemini for the last call
+func (p *GeminiProvider) LastUsage() Usage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastUsage
+}
+
 func (p *GeminiProvider) Close() error {
 	// Client might not need close or has Close()
 	// It usually doesn't if it's http based, but let's check.
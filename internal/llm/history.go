@@ -0,0 +1,123 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// historyTokenBudget bounds the tokens spent on previous turns
+	historyTokenBudget = 900
+	// maxHistoryTurns is the most turns sent verbatim, even within budget
+	maxHistoryTurns = 6
+	// maxSummaryLines bounds how many older turns get their own summary line
+	maxSummaryLines = 8
+	// summaryOutcomeChars truncates outcomes in the summary of older turns
+	summaryOutcomeChars = 80
+)
+
+// CombatTurn is a previous action in the current fight
+type CombatTurn struct {
+	Action         string
+	Score          int
+	DamageDealt    int
+	DamageReceived int
+	Outcome        string
+	ErrorCategory  string           // Main type of mistake, for enemy abilities
+	Reply          CombatAssessment // Final assessment, replayed as the DM's reply
+}
+
+// estimateTokens roughly counts tokens, at about four characters per token
+func estimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// turnMessages renders a turn as the user action and the DM's reply. The
+// reply carries every field of the schema, since the model copies the
+// shape of earlier replies.
+func turnMessages(t CombatTurn) []ChatMessage {
+	reply, _ := json.Marshal(t.Reply)
+	return []ChatMessage{
+		{Role: "user", Content: wrapPlayerText(SanitizeInput(t.Action))},
+		{Role: "assistant", Content: string(reply)},
+	}
+}
+
+// summarizeTurns condenses older turns into a short recap
+func summarizeTurns(turns []CombatTurn) string {
+	var sb strings.Builder
+	sb.WriteString("EARLIER IN THIS FIGHT:\n")
+
+	skipped := len(turns) - maxSummaryLines
+	if skipped > 0 {
+		total := 0
+		for _, t := range turns[:skipped] {
+			total += t.Score
+		}
+		fmt.Fprintf(&sb, "- %d earlier turns with an average score of %.1f.\n", skipped, float64(total)/float64(skipped))
+		turns = turns[skipped:]
+	}
+
+	for _, t := range turns {
+		outcome := t.Outcome
+		if r := []rune(outcome); len(r) > summaryOutcomeChars {
+			outcome = string(r[:summaryOutcomeChars]) + "..."
+		}
+		fmt.Fprintf(&sb, "- Score %d, dealt %d, took %d: %s\n", t.Score, t.DamageDealt, t.DamageReceived, outcome)
+	}
+	return sb.String()
+}
+
+// buildCombatMessages builds the multi-turn conversation for a combat
+// action: the system prompt rendered with the current HP values and a
+// recap of older turns, the most recent turns within the token budget,
+// and the new action. It also returns the prompt template used.
+func buildCombatMessages(req CombatRequest) ([]ChatMessage, *Prompt, error) {
+	// Keep as many recent turns as fit, newest first
+	budget := historyTokenBudget
+	keep := 0
+	for i := len(req.History) - 1; i >= 0 && keep < maxHistoryTurns; i-- {
+		cost := 0
+		for _, m := range turnMessages(req.History[i]) {
+			cost += estimateTokens(m.Content)
+		}
+		if cost > budget {
+			break
+		}
+		budget -= cost
+		keep++
+	}
+
+	profile := req.Level.Profile()
+	data := PromptData{
+		Enemy:           req.Enemy,
+		Location:        req.Location,
+		Tier:            req.Tier,
+		GrammarFocus:    req.Focus,
+		Difficulty:      req.Level,
+		Strictness:      profile.Strictness,
+		Structures:      profile.Structures,
+		Vocabulary:      profile.Vocabulary,
+		PlayerHP:        req.PlayerHP,
+		PlayerMaxHP:     req.PlayerMaxHP,
+		EnemyHP:         req.EnemyHP,
+		EnemyMaxHP:      req.EnemyMaxHP,
+		HasHistory:      len(req.History) > 0,
+		ExplanationRule: explanationRule(req.ExplanationLanguage),
+	}
+	if older := req.History[:len(req.History)-keep]; len(older) > 0 {
+		data.History = summarizeTurns(older)
+	}
+
+	system, prompt, err := renderPrompt(CombatPromptName, req.Persona, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	messages := []ChatMessage{{Role: "system", Content: system}}
+	for _, t := range req.History[len(req.History)-keep:] {
+		messages = append(messages, turnMessages(t)...)
+	}
+	return append(messages, ChatMessage{Role: "user", Content: wrapPlayerText(req.Action)}), prompt, nil
+}
@@ -6,19 +6,114 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 )
 
 const (
 	serverURL = "http://127.0.0.1:8080/v1/chat/completions"
+	modelsURL = "http://127.0.0.1:8080/v1/models"
 )
 
-type LlamaCppProvider struct{}
+// modelLookupTimeout bounds the model name lookup, which must never hang
+// the game on a server that is down
+const modelLookupTimeout = 2 * time.Second
+
+var modelClient = &http.Client{Timeout: modelLookupTimeout}
+
+// A failed lookup is not retried by Call until its backoff runs out, so a
+// server that is slow or down does not add the lookup timeout to every turn
+const (
+	modelLookupBackoff    = 30 * time.Second
+	maxModelLookupBackoff = 5 * time.Minute
+)
+
+// llamaModel is the model the server reported. Only a successful lookup is
+// stored, so a server started later is picked up once the backoff ends.
+var (
+	llamaModelMu sync.Mutex
+	llamaModel   string
+	llamaBackoff time.Duration // Doubles with each failed lookup in a row
+	llamaRetryAt time.Time     // Call does not look the model up before this
+)
+
+type LlamaCppProvider struct {
+	mu        sync.Mutex
+	lastUsage Usage
+}
 
 func NewLlamaCppProvider() *LlamaCppProvider {
 	return &LlamaCppProvider{}
 }
 
+type modelsResponse struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// ResolveLlamaCppModel asks the server which GGUF file it has loaded and
+// remembers it for Name. Call it at startup, outside the UI loop; Call
+// retries it while the model is still unknown, with a growing backoff.
+func ResolveLlamaCppModel() (string, error) {
+	model, err := lookupLlamaModel()
+
+	llamaModelMu.Lock()
+	defer llamaModelMu.Unlock()
+	if err != nil {
+		llamaBackoff = min(max(2*llamaBackoff, modelLookupBackoff), maxModelLookupBackoff)
+		llamaRetryAt = time.Now().Add(llamaBackoff)
+		return "", err
+	}
+	llamaModel = model
+	llamaBackoff = 0
+	return model, nil
+}
+
+// lookupLlamaModel asks the server for the model it has loaded
+func lookupLlamaModel() (string, error) {
+	resp, err := modelClient.Get(modelsURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to ask llama.cpp for its model: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var models modelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return "", fmt.Errorf("failed to parse llama.cpp models: %w", err)
+	}
+	if len(models.Data) == 0 {
+		return "", fmt.Errorf("llama.cpp reported no model")
+	}
+
+	return filepath.Base(models.Data[0].ID), nil
+}
+
+// knownModel returns the model name from the last successful lookup
+func knownModel() string {
+	llamaModelMu.Lock()
+	defer llamaModelMu.Unlock()
+	return llamaModel
+}
+
+// modelLookupDue reports whether Call should look the model up again
+func modelLookupDue(now time.Time) bool {
+	llamaModelMu.Lock()
+	defer llamaModelMu.Unlock()
+	return llamaModel == "" && !now.Before(llamaRetryAt)
+}
+
+// Name returns "llamacpp/<model>", or "llamacpp" while the model is
+// unknown. It never touches the network.
+func (p *LlamaCppProvider) Name() string {
+	if model := knownModel(); model != "" {
+		return "llamacpp/" + model
+	}
+	return "llamacpp"
+}
+
 type chatRequest struct {
 	Messages    []ChatMessage `json:"messages"`
 	Temperature float64       `json:"temperature"`
@@ -30,30 +125,44 @@ type chatResponse struct {
 	Choices []struct {
 		Message ChatMessage `json:"message"`
 	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
 }
 
-func parseLLMResponse(resp *http.Response, err error) (string, error) {
+func parseLLMResponse(resp *http.Response, err error) (string, Usage, error) {
 	if err != nil {
-		return "", fmt.Errorf("failed to call llm server: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to call llm server: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("server error: %s", string(body))
+		return "", Usage{}, &StatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			Message:    string(body),
+		}
 	}
 
 	var result chatResponse
 	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	usage := Usage{
+		Calls:            1,
+		PromptTokens:     result.Usage.PromptTokens,
+		CompletionTokens: result.Usage.CompletionTokens,
 	}
 
 	if len(result.Choices) == 0 {
-		return "", fmt.Errorf("no content received from LLM")
+		return "", usage, fmt.Errorf("no content received from LLM")
 	}
 
 	// Clean up the output (remove extra spaces or newlines)
@@ -66,11 +175,15 @@ func parseLLMResponse(resp *http.Response, err error) (string, error) {
 
 	cleanText = strings.ReplaceAll(cleanText, "‘", "'")
 
-	return cleanText, nil
+	return cleanText, usage, nil
 }
 
 // Call sends a request to the local LLM
 func (p *LlamaCppProvider) Call(messages []ChatMessage) (string, error) {
+	if modelLookupDue(time.Now()) {
+		ResolveLlamaCppModel()
+	}
+
 	reqBody := chatRequest{
 		Messages:    messages,
 		Temperature: 0.7,
@@ -84,5 +197,18 @@ func (p *LlamaCppProvider) Call(messages []ChatMessage) (string, error) {
 	}
 
 	resp, err := http.Post(serverURL, "application/json", bytes.NewBuffer(jsonData))
-	return parseLLMResponse(resp, err)
+	text, usage, err := parseLLMResponse(resp, err)
+
+	p.mu.Lock()
+	p.lastUsage = usage
+	p.mu.Unlock()
+
+	return text, err
+}
+
+// LastUsage returns the token usage reported by the server for the last call
+func (p *LlamaCppProvider) LastUsage() Usage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastUsage
 }
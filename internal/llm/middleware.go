@@ -0,0 +1,281 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Middleware wraps a Provider with extra behavior
+type Middleware func(Provider) Provider
+
+// Chain wraps p with the middlewares. The first middleware is the
+// outermost, so it sees every call first.
+func Chain(p Provider, middlewares ...Middleware) Provider {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		p = middlewares[i](p)
+	}
+	return p
+}
+
+// providerFunc adapts a name and a call function to Provider. The name is
+// a function so wrappers follow providers whose name changes, like a
+// MeteredProvider over budget. Evictions are forwarded to the cache below.
+type providerFunc struct {
+	name  func() string
+	call  func([]ChatMessage) (string, error)
+	evict func([]ChatMessage) // nil if nothing below caches
+}
+
+func (p providerFunc) Name() string {
+	return p.name()
+}
+
+func (p providerFunc) Call(messages []ChatMessage) (string, error) {
+	return p.call(messages)
+}
+
+func (p providerFunc) Evict(messages []ChatMessage) {
+	if p.evict != nil {
+		p.evict(messages)
+	}
+}
+
+// evictFunc returns the eviction of next, or nil if it can't evict
+func evictFunc(next Provider) func([]ChatMessage) {
+	if e, ok := next.(Evicter); ok {
+		return e.Evict
+	}
+	return nil
+}
+
+// WithCache caches replies on disk, see CachedProvider
+func WithCache(dir string, ttl time.Duration, maxBytes int64) Middleware {
+	return func(next Provider) Provider {
+		return NewCachedProvider(next, dir, ttl, maxBytes)
+	}
+}
+
+// maxLoggedChars truncates logged prompts and replies
+const maxLoggedChars = 4000
+
+// redactions hide secrets that could end up in prompts or server replies
+var redactions = []struct {
+	pattern *regexp.Regexp
+	replace string
+}{
+	{regexp.MustCompile(`AIza[0-9A-Za-z_\-]{35}`), "[REDACTED_API_KEY]"},
+	{regexp.MustCompile(`\bsk-[0-9A-Za-z_\-]{16,}`), "[REDACTED_API_KEY]"},
+	{regexp.MustCompile(`(?i)(bearer\s+)[0-9A-Za-z._\-]+`), "${1}[REDACTED]"},
+	{regexp.MustCompile(`(?i)((?:api[_-]?key|token|password)"?\s*[:=]\s*"?)[^\s",]+`), "${1}[REDACTED]"},
+	{regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`), "[REDACTED_EMAIL]"},
+}
+
+// Redact hides API keys, tokens and email addresses in s
+func Redact(s string) string {
+	for _, r := range redactions {
+		s = r.pattern.ReplaceAllString(s, r.replace)
+	}
+	return s
+}
+
+func truncate(s string) string {
+	if r := []rune(s); len(r) > maxLoggedChars {
+		return string(r[:maxLoggedChars]) + "...(truncated)"
+	}
+	return s
+}
+
+// WithLogging writes every call to the standard logger (debug.log), with
+// the prompts and raw replies if asked, after redacting secrets
+func WithLogging(prompts, responses bool) Middleware {
+	return func(next Provider) Provider {
+		return providerFunc{name: next.Name, evict: evictFunc(next), call: func(messages []ChatMessage) (string, error) {
+			start := time.Now()
+			resp, err := next.Call(messages)
+			latency := time.Since(start).Round(time.Millisecond)
+
+			if err != nil {
+				log.Printf("llm call %s %s failed: %s", next.Name(), latency, Redact(err.Error()))
+			} else {
+				log.Printf("llm call %s %s ok", next.Name(), latency)
+			}
+			if prompts {
+				for _, m := range messages {
+					log.Printf("  prompt[%s]: %s", m.Role, truncate(Redact(m.Content)))
+				}
+			}
+			if responses && err == nil {
+				log.Printf("  response: %s", truncate(Redact(resp)))
+			}
+			return resp, err
+		}}
+	}
+}
+
+// LatencyBuckets are the upper bounds of the latency histogram. Slower
+// calls go in a last, unbounded bucket.
+var LatencyBuckets = []time.Duration{
+	250 * time.Millisecond, 500 * time.Millisecond, time.Second,
+	2 * time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second,
+}
+
+// ProviderMetrics are the call statistics of one provider
+type ProviderMetrics struct {
+	Name         string
+	Calls        int
+	Errors       int
+	ErrorKinds   map[string]int // e.g. "429", "unreachable" or "other"
+	Buckets      []int          // Calls per latency bucket, one more than LatencyBuckets
+	TotalLatency time.Duration
+}
+
+// MeanLatency returns the mean latency of the calls
+func (m ProviderMetrics) MeanLatency() time.Duration {
+	if m.Calls == 0 {
+		return 0
+	}
+	return m.TotalLatency / time.Duration(m.Calls)
+}
+
+// Metrics collects latency histograms and error counters per provider
+// for the session
+type Metrics struct {
+	mu        sync.Mutex
+	providers map[string]*ProviderMetrics
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{providers: map[string]*ProviderMetrics{}}
+}
+
+func (m *Metrics) record(name string, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pm, ok := m.providers[name]
+	if !ok {
+		pm = &ProviderMetrics{
+			Name:       name,
+			ErrorKinds: map[string]int{},
+			Buckets:    make([]int, len(LatencyBuckets)+1),
+		}
+		m.providers[name] = pm
+	}
+
+	pm.Calls++
+	pm.TotalLatency += latency
+	bucket := sort.Search(len(LatencyBuckets), func(i int) bool { return latency <= LatencyBuckets[i] })
+	pm.Buckets[bucket]++
+	if err != nil {
+		pm.Errors++
+		pm.ErrorKinds[errorKind(err)]++
+	}
+}
+
+// Snapshot returns a copy of the metrics, sorted by provider name
+func (m *Metrics) Snapshot() []ProviderMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []ProviderMetrics
+	for _, pm := range m.providers {
+		c := *pm
+		c.Buckets = append([]int(nil), pm.Buckets...)
+		c.ErrorKinds = make(map[string]int, len(pm.ErrorKinds))
+		for k, v := range pm.ErrorKinds {
+			c.ErrorKinds[k] = v
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Summary renders the metrics as text, for the log at exit
+func (m *Metrics) Summary() string {
+	var sb strings.Builder
+	for _, pm := range m.Snapshot() {
+		fmt.Fprintf(&sb, "%s: %d calls, %d errors %v, mean latency %s, histogram",
+			pm.Name, pm.Calls, pm.Errors, pm.ErrorKinds, pm.MeanLatency().Round(time.Millisecond))
+		for i, n := range pm.Buckets {
+			fmt.Fprintf(&sb, " %s:%d", BucketLabel(i), n)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// BucketLabel names a latency bucket, e.g. "≤500ms" or ">30s"
+func BucketLabel(i int) string {
+	if i < len(LatencyBuckets) {
+		return "≤" + LatencyBuckets[i].String()
+	}
+	return ">" + LatencyBuckets[len(LatencyBuckets)-1].String()
+}
+
+// errorKind groups errors for the counters
+func errorKind(err error) string {
+	var se *StatusError
+	switch {
+	case errors.As(err, &se):
+		return fmt.Sprint(se.StatusCode)
+	case strings.Contains(err.Error(), "connection refused"):
+		return "unreachable"
+	}
+	return "other"
+}
+
+// WithMetrics records the latency and errors of every call in m
+func WithMetrics(m *Metrics) Middleware {
+	return func(next Provider) Provider {
+		return providerFunc{name: next.Name, evict: evictFunc(next), call: func(messages []ChatMessage) (string, error) {
+			start := time.Now()
+			resp, err := next.Call(messages)
+			m.record(next.Name(), time.Since(start), err)
+			return resp, err
+		}}
+	}
+}
+
+// WithTracing wraps every call in an OpenTelemetry span. Spans go to the
+// global tracer provider, so they are only exported when one is set up,
+// e.g. by OpenTelemetry Go auto-instrumentation.
+func WithTracing() Middleware {
+	tracer := otel.Tracer("github.com/erwaen/type-glish/internal/llm")
+	return func(next Provider) Provider {
+		return providerFunc{name: next.Name, evict: evictFunc(next), call: func(messages []ChatMessage) (string, error) {
+			_, span := tracer.Start(context.Background(), "llm.call")
+			defer span.End()
+
+			chars := 0
+			for _, m := range messages {
+				chars += len(m.Content)
+			}
+			span.SetAttributes(
+				attribute.String("llm.provider", next.Name()),
+				attribute.Int("llm.messages", len(messages)),
+				attribute.Int("llm.prompt_chars", chars),
+			)
+
+			resp, err := next.Call(messages)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, Redact(err.Error()))
+			} else {
+				span.SetAttributes(attribute.Int("llm.response_chars", len(resp)))
+			}
+			return resp, err
+		}}
+	}
+}
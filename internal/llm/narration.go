@@ -0,0 +1,83 @@
+package llm
+
+import (
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erwaen/type-glish/internal/cefr"
+)
+
+// maxNarrationRunes caps narration from a model that ignores the length rule
+const maxNarrationRunes = 600
+
+// NarrationRequest holds what the DM needs to narrate a scene transition
+type NarrationRequest struct {
+	Scene       string // "arrival", "encounter" or "crossroads"
+	Enemy       string
+	Location    string
+	Description string   // Enemy description
+	Paths       []string // Paths at a crossroads
+	Recap       string   // The run so far
+	Level       cefr.Level
+	Persona     string // DM persona ID, empty for the default
+}
+
+// NarrationMsg carries the narration of a scene
+type NarrationMsg struct {
+	Text string
+	Err  error
+}
+
+// Narrate asks the DM for a short description of a scene. Narration
+// never goes through the response cache, or every visit to the same enemy
+// and location would get the same text.
+func (c *Client) Narrate(req NarrationRequest) tea.Msg {
+	prompt, _, err := renderPrompt(NarratorPromptName, req.Persona, PromptData{
+		Scene:       req.Scene,
+		Enemy:       req.Enemy,
+		Location:    req.Location,
+		Description: req.Description,
+		Options:     strings.Join(req.Paths, "\n"),
+		Recap:       req.Recap,
+		Difficulty:  req.Level,
+		Vocabulary:  req.Level.Profile().Vocabulary,
+	})
+	if err != nil {
+		return NarrationMsg{Err: err}
+	}
+
+	resp, err := c.provider.Call([]ChatMessage{
+		{Role: "system", Content: prompt},
+		{Role: "user", Content: "Narrate the scene.", NoCache: true},
+	})
+	if err != nil {
+		return NarrationMsg{Err: err}
+	}
+
+	text := cleanNarration(resp)
+	if text == "" {
+		return NarrationMsg{Err: errors.New("empty narration")}
+	}
+	return NarrationMsg{Text: text}
+}
+
+// cleanNarration strips markdown fences and quotes around the text and
+// caps its length at a sentence end when possible
+func cleanNarration(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "```")
+	s = strings.TrimSuffix(s, "```")
+	s = strings.Trim(strings.TrimSpace(s), `"`)
+	s = strings.Join(strings.Fields(s), " ")
+
+	if r := []rune(s); len(r) > maxNarrationRunes {
+		s = string(r[:maxNarrationRunes])
+		if i := strings.LastIndexAny(s, ".!?"); i > 0 {
+			s = s[:i+1]
+		} else {
+			s += "..."
+		}
+	}
+	return s
+}
@@ -0,0 +1,51 @@
+package llm
+
+// Persona is a Dungeon Master voice. Its tone is injected into every
+// prompt, so the DM comments, outcomes and narration all stay in character.
+type Persona struct {
+	ID   string // Stored in the config, also the message catalog key "persona.<id>"
+	Name string // English name, used inside the prompts
+	Tone string // Tone instructions for the model
+}
+
+// DefaultPersona is used when the config has no (or an unknown) persona
+const DefaultPersona = "wizard"
+
+// Personas lists every DM persona in the order Settings cycles through them
+var Personas = []Persona{
+	{
+		ID:   "tutor",
+		Name: "Encouraging Tutor",
+		Tone: "You are a warm, patient English tutor. Praise what the player did well before pointing out mistakes, never mock them, and keep every comment kind and motivating.",
+	},
+	{
+		ID:   "wizard",
+		Name: "Grumpy Wizard",
+		Tone: "You are an ancient, slightly grumpy but wise wizard. You speak with gravitas and are snarky about bad grammar, but never cruel.",
+	},
+	{
+		ID:   "pirate",
+		Name: "Pirate Captain",
+		Tone: "You are a boisterous pirate captain. Speak with pirate flavor (\"Arr\", \"matey\", \"ye\") and playful teasing, but keep the corrected sentence in plain standard English.",
+	},
+	{
+		ID:   "examiner",
+		Name: "Formal Examiner",
+		Tone: "You are a formal, neutral language examiner. Be precise, polite and impersonal, with no jokes or sarcasm; state what was right and what was wrong.",
+	},
+}
+
+// GetPersona returns the persona with the given ID, or the default one
+func GetPersona(id string) Persona {
+	for _, p := range Personas {
+		if p.ID == id {
+			return p
+		}
+	}
+	for _, p := range Personas {
+		if p.ID == DefaultPersona {
+			return p
+		}
+	}
+	return Personas[0]
+}
@@ -3,4 +3,7 @@ package llm
 // Provider defines the interface for LLM backends
 type Provider interface {
 	Call(messages []ChatMessage) (string, error)
+
+	// Name identifies the backend and model, e.g. "gemini/gemini-3-flash-preview"
+	Name() string
 }
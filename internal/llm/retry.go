@@ -0,0 +1,194 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Retry defaults
+const (
+	DefaultMaxRetries = 3
+	retryBaseDelay    = time.Second
+	retryMaxDelay     = 8 * time.Second
+	maxRetryAfter     = 30 * time.Second // Longer waits give up instead of freezing the game
+)
+
+// StatusError is an error response from an LLM server
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration // Zero when the server did not say
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("server error (%d): %s", e.StatusCode, e.Message)
+}
+
+// parseRetryAfter reads a Retry-After header, in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(0, time.Until(t))
+	}
+	return 0
+}
+
+// isRetryable reports whether an error is worth another attempt: rate
+// limits, overloaded or loading servers and timeouts. Refused connections
+// are not retried, so a missing server falls back to offline grading fast.
+func isRetryable(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		switch se.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// RetryStatus describes a retry or rate limit wait in progress
+type RetryStatus struct {
+	Attempt     int // Attempt about to be made, starting at 2
+	MaxAttempts int
+	Until       time.Time // End of the wait
+	Reason      string    // Short description of the last error
+	RateLimited bool      // Waiting for the requests-per-minute limit, not for a retry
+}
+
+// RetryProvider retries retryable errors of a provider with exponential
+// backoff and jitter, honors Retry-After and enforces a requests-per-minute
+// limit. The current wait is exposed with Status for the spinner views.
+type RetryProvider struct {
+	next        Provider
+	maxAttempts int
+	rpm         int // 0 for no limit
+
+	now   func() time.Time
+	sleep func(time.Duration)
+
+	mu     sync.Mutex
+	calls  []time.Time // Start of the calls in the last minute
+	status *RetryStatus
+}
+
+// NewRetryProvider wraps next. maxRetries of 0 uses the default, rpm is
+// the requests-per-minute limit, 0 for none.
+func NewRetryProvider(next Provider, maxRetries, rpm int) *RetryProvider {
+	if maxRetries <= 0 {
+		maxRetries = DefaultMaxRetries
+	}
+	return &RetryProvider{next: next, maxAttempts: maxRetries + 1, rpm: rpm, now: time.Now, sleep: time.Sleep}
+}
+
+func (p *RetryProvider) Name() string {
+	return p.next.Name()
+}
+
+// Status returns the wait in progress, if any
+func (p *RetryProvider) Status() (RetryStatus, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.status == nil {
+		return RetryStatus{}, false
+	}
+	return *p.status, true
+}
+
+func (p *RetryProvider) setStatus(s *RetryStatus) {
+	p.mu.Lock()
+	p.status = s
+	p.mu.Unlock()
+}
+
+func (p *RetryProvider) Call(messages []ChatMessage) (string, error) {
+	defer p.setStatus(nil)
+
+	var err error
+	for attempt := 1; attempt <= p.maxAttempts; attempt++ {
+		p.waitForSlot()
+
+		var resp string
+		resp, err = p.next.Call(messages)
+		if err == nil || !isRetryable(err) || attempt == p.maxAttempts {
+			return resp, err
+		}
+
+		delay := backoff(attempt)
+		var se *StatusError
+		if errors.As(err, &se) && se.RetryAfter > 0 {
+			if se.RetryAfter > maxRetryAfter {
+				return "", fmt.Errorf("server asked to wait %s: %w", se.RetryAfter.Round(time.Second), err)
+			}
+			delay = se.RetryAfter
+		}
+
+		log.Printf("LLM call failed (attempt %d/%d), retrying in %s: %v", attempt, p.maxAttempts, delay.Round(time.Millisecond), err)
+		p.setStatus(&RetryStatus{
+			Attempt:     attempt + 1,
+			MaxAttempts: p.maxAttempts,
+			Until:       p.now().Add(delay),
+			Reason:      retryReason(err),
+		})
+		p.sleep(delay)
+	}
+	return "", err
+}
+
+// waitForSlot blocks until a call fits in the requests-per-minute limit
+// and records it
+func (p *RetryProvider) waitForSlot() {
+	if p.rpm <= 0 {
+		return
+	}
+	for {
+		p.mu.Lock()
+		now := p.now()
+		cutoff := now.Add(-time.Minute)
+		for len(p.calls) > 0 && !p.calls[0].After(cutoff) {
+			p.calls = p.calls[1:]
+		}
+		if len(p.calls) < p.rpm {
+			p.calls = append(p.calls, now)
+			p.mu.Unlock()
+			return
+		}
+		wait := p.calls[0].Add(time.Minute).Sub(now)
+		p.mu.Unlock()
+
+		p.setStatus(&RetryStatus{Until: now.Add(wait), RateLimited: true})
+		p.sleep(wait)
+	}
+}
+
+// backoff returns the delay before the next attempt: exponential from
+// retryBaseDelay, capped at retryMaxDelay, with jitter over its upper half
+func backoff(attempt int) time.Duration {
+	delay := min(retryMaxDelay, retryBaseDelay<<(attempt-1))
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// retryReason names an error for the spinner, e.g. "429" or "timeout"
+func retryReason(err error) string {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return strconv.Itoa(se.StatusCode)
+	}
+	return "timeout"
+}
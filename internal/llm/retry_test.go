@@ -0,0 +1,263 @@
+package llm
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// flakyProvider fails with its errors in order, then succeeds
+type flakyProvider struct {
+	errs  []error
+	calls int
+}
+
+func (f *flakyProvider) Name() string { return "flaky" }
+
+func (f *flakyProvider) Call(messages []ChatMessage) (string, error) {
+	f.calls++
+	if f.calls <= len(f.errs) {
+		return "", f.errs[f.calls-1]
+	}
+	return "ok", nil
+}
+
+// fakeClock is a clock that only moves when the code sleeps
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+	onWait func()
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	if c.onWait != nil {
+		c.onWait()
+	}
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+}
+
+func newTestRetry(next Provider, maxRetries, rpm int) (*RetryProvider, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	p := NewRetryProvider(next, maxRetries, rpm)
+	p.now = clock.Now
+	p.sleep = clock.Sleep
+	return p, clock
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func status(code int) error {
+	return &StatusError{StatusCode: code, Message: http.StatusText(code)}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		full    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, retryMaxDelay},
+		{10, retryMaxDelay},
+	}
+	for _, tt := range tests {
+		for range 200 {
+			got := backoff(tt.attempt)
+			if got < tt.full/2 || got > tt.full {
+				t.Fatalf("backoff(%d) = %s, want between %s and %s", tt.attempt, got, tt.full/2, tt.full)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"0", 0},
+		{"7", 7 * time.Second},
+		{"soon", 0},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+
+	future := time.Now().Add(20 * time.Second).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(future); got < 18*time.Second || got > 20*time.Second {
+		t.Errorf("parseRetryAfter(%q) = %s, want about 20s", future, got)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limited", status(http.StatusTooManyRequests), true},
+		{"server error", status(http.StatusInternalServerError), true},
+		{"bad gateway", status(http.StatusBadGateway), true},
+		{"unavailable", status(http.StatusServiceUnavailable), true},
+		{"gateway timeout", status(http.StatusGatewayTimeout), true},
+		{"bad request", status(http.StatusBadRequest), false},
+		{"unauthorized", status(http.StatusUnauthorized), false},
+		{"wrapped status", errors.Join(errors.New("gemini"), status(http.StatusServiceUnavailable)), true},
+		{"timeout", timeoutError{}, true},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, false},
+		{"other error", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		if got := isRetryable(tt.err); got != tt.want {
+			t.Errorf("%s: isRetryable = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRetryProviderCall(t *testing.T) {
+	tests := []struct {
+		name       string
+		errs       []error
+		maxRetries int
+		wantCalls  int
+		wantErr    bool
+		wantSleeps []time.Duration // nil to only check the backoff bounds
+		sleeps     int
+	}{
+		{
+			name:      "success",
+			wantCalls: 1,
+		},
+		{
+			name:      "recovers after retryable errors",
+			errs:      []error{status(http.StatusServiceUnavailable), timeoutError{}},
+			wantCalls: 3,
+			sleeps:    2,
+		},
+		{
+			name:      "does not retry other errors",
+			errs:      []error{status(http.StatusBadRequest)},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:       "gives up after the last attempt",
+			errs:       []error{status(500), status(500), status(500), status(500)},
+			maxRetries: 2,
+			wantCalls:  3,
+			wantErr:    true,
+			sleeps:     2,
+		},
+		{
+			name:       "honors Retry-After",
+			errs:       []error{&StatusError{StatusCode: 429, RetryAfter: 5 * time.Second}},
+			wantCalls:  2,
+			wantSleeps: []time.Duration{5 * time.Second},
+			sleeps:     1,
+		},
+		{
+			name:      "gives up on long Retry-After",
+			errs:      []error{&StatusError{StatusCode: 429, RetryAfter: time.Minute}},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &flakyProvider{errs: tt.errs}
+			p, clock := newTestRetry(next, tt.maxRetries, 0)
+
+			resp, err := p.Call(nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Call error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && resp != "ok" {
+				t.Errorf("Call = %q, want %q", resp, "ok")
+			}
+			if next.calls != tt.wantCalls {
+				t.Errorf("provider called %d times, want %d", next.calls, tt.wantCalls)
+			}
+			if len(clock.sleeps) != tt.sleeps {
+				t.Fatalf("slept %v, want %d sleeps", clock.sleeps, tt.sleeps)
+			}
+			for i, d := range clock.sleeps {
+				if tt.wantSleeps != nil {
+					if d != tt.wantSleeps[i] {
+						t.Errorf("sleep %d = %s, want %s", i+1, d, tt.wantSleeps[i])
+					}
+				} else if full := min(retryMaxDelay, retryBaseDelay<<i); d < full/2 || d > full {
+					t.Errorf("sleep %d = %s, want between %s and %s", i+1, d, full/2, full)
+				}
+			}
+			if _, ok := p.Status(); ok {
+				t.Error("Status still set after the call")
+			}
+		})
+	}
+}
+
+func TestRetryProviderStatus(t *testing.T) {
+	next := &flakyProvider{errs: []error{&StatusError{StatusCode: 503, RetryAfter: 3 * time.Second}}}
+	p, clock := newTestRetry(next, 0, 0)
+
+	var got RetryStatus
+	var ok bool
+	clock.onWait = func() { got, ok = p.Status() }
+	p.Call(nil)
+
+	want := RetryStatus{Attempt: 2, MaxAttempts: DefaultMaxRetries + 1, Until: clock.now, Reason: "503"}
+	if !ok || got != want {
+		t.Errorf("Status while waiting = %+v, %v, want %+v", got, ok, want)
+	}
+}
+
+func TestRetryProviderRateLimit(t *testing.T) {
+	next := &flakyProvider{}
+	p, clock := newTestRetry(next, 0, 2)
+	start := clock.now
+
+	var waits []RetryStatus
+	clock.onWait = func() {
+		s, _ := p.Status()
+		waits = append(waits, s)
+	}
+
+	// Two calls fit in the minute, the third waits for the first to age out
+	p.Call(nil)
+	clock.now = start.Add(10 * time.Second)
+	p.Call(nil)
+	clock.now = start.Add(20 * time.Second)
+	p.Call(nil)
+
+	if len(clock.sleeps) != 1 || clock.sleeps[0] != 40*time.Second {
+		t.Fatalf("slept %v, want one wait of 40s", clock.sleeps)
+	}
+	if !waits[0].RateLimited || !waits[0].Until.Equal(start.Add(time.Minute)) {
+		t.Errorf("Status while rate limited = %+v, want RateLimited until %s", waits[0], start.Add(time.Minute))
+	}
+
+	// Once a minute has passed without calls, there is no wait
+	clock.now = clock.now.Add(2 * time.Minute)
+	p.Call(nil)
+	p.Call(nil)
+	if len(clock.sleeps) != 1 {
+		t.Errorf("slept %v after the window cleared, want no new waits", clock.sleeps)
+	}
+	if next.calls != 5 {
+		t.Errorf("provider called %d times, want 5", next.calls)
+	}
+}
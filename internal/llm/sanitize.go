@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// MaxInputRunes is the longest player text sent to the model
+const MaxInputRunes = 200
+
+// Delimiters around player text in prompts. SanitizeInput strips anything
+// that could close them early.
+const (
+	playerTextStart = "<<<PLAYER_TEXT"
+	playerTextEnd   = "PLAYER_TEXT>>>"
+)
+
+// injectionPatterns match attempts to talk to the grader instead of the
+// game. They are anchored on imperative or meta phrasing, since learners
+// write things like "I make damage to the goblin" or "I ignore the rules
+// of honor" in good faith.
+var injectionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\s+(all\s+|any\s+)?(of\s+)?(the\s+|your\s+|these\s+|those\s+)?(previous\s+|prior\s+|above\s+|earlier\s+|system\s+|grading\s+)?(instructions?|prompts?)\b`),
+	regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\s+(all\s+)?(your|the\s+(previous|above|grading|scoring))\s+rules\b`),
+	regexp.MustCompile(`(?i)\b(system|developer|hidden)\s+(prompt|message|instructions?)\b`),
+	regexp.MustCompile(`(?i)\byou\s+are\s+now\s+(a\s+|an\s+|the\s+|my\s+)?(dm|dungeon\s+master|judge|grader|assistant|ai|chatbot|language\s+model)\b`),
+	regexp.MustCompile(`(?i)\bnew\s+instructions?\s*:`),
+	regexp.MustCompile(`(?i)\b((act|pretend|behave)\s+(as|like)|pretend\s+to\s+be)\s+(the\s+)?(dm|dungeon\s+master|judge|grader|system|assistant)\b`),
+	regexp.MustCompile(`(?i)"(score|damage_dealt|damage_received|healing|is_relevant)"\s*:`),
+	regexp.MustCompile(`(?i)\b(score|damage_dealt|damage_received|is_relevant)\s*[:=]\s*(\d+|true|false)\b`),
+	regexp.MustCompile(`(?i)\b(set|change|make)\s+(my|the)\s+(score|damage|healing|hp)\s+(to|=)\s*\d+`),
+	regexp.MustCompile(`(?i)\b(give|grant|award)\s+me\s+(a\s+|the\s+)?(perfect\s+score|full\s+score|max(imum)?\s+(score|damage)|10\s*/\s*10|(a\s+)?score\s+of\s+\d+)`),
+}
+
+// SanitizeInput removes control characters and prompt delimiters from
+// player text, collapses whitespace and caps the length
+func SanitizeInput(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+	s = strings.NewReplacer("<<<", "", ">>>", "").Replace(s)
+	s = strings.Join(strings.Fields(s), " ")
+
+	if r := []rune(s); len(r) > MaxInputRunes {
+		s = string(r[:MaxInputRunes])
+	}
+	return s
+}
+
+// DetectInjection reports whether player text looks like an attempt to
+// give the grader instructions
+func DetectInjection(s string) bool {
+	for _, p := range injectionPatterns {
+		if p.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// wrapPlayerText delimits sanitized player text so the model can tell it
+// apart from instructions
+func wrapPlayerText(s string) string {
+	return playerTextStart + "\n" + s + "\n" + playerTextEnd
+}
@@ -0,0 +1,246 @@
+package llm
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/erwaen/type-glish/internal/cefr"
+)
+
+// Prompt template names, which are also the file names without .tmpl
+const (
+	CriticPromptName   = "critic"
+	CombatPromptName   = "combat"
+	PathPromptName     = "path"
+	NarratorPromptName = "narrator"
+	BestiaryPromptName = "bestiary"
+)
+
+//go:embed prompts/*.tmpl
+var embeddedPrompts embed.FS
+
+// versionTag finds the version comment at the top of a template, e.g.
+// {{- /* version: 2 */ -}}
+var versionTag = regexp.MustCompile(`/\*\s*version:\s*(\S+)\s*\*/`)
+
+// PromptData holds the named variables available to the prompt templates.
+// Fields that do not apply to a prompt are left at their zero value.
+type PromptData struct {
+	Persona string // DM persona name, e.g. "Grumpy Wizard"
+	Tone    string // Tone instructions of the persona
+
+	Enemy      string
+	Location   string
+	Tier       int        // Enemy tier, 1=easy to 4=boss, 0 if unknown
+	Difficulty cefr.Level // CEFR level of the player
+	Strictness string
+	Structures string
+	Vocabulary string
+	Options    string // Available paths
+
+	GrammarFocus string // Grammar area the enemy embodies, may be empty
+
+	PlayerHP    int
+	PlayerMaxHP int
+	EnemyHP     int
+	EnemyMaxHP  int
+	HasHistory  bool   // Earlier turns of the fight are in the conversation
+	History     string // Recap of the turns too old to send verbatim
+
+	ExplanationRule string
+	PlayerTextRule  string
+
+	// Narration
+	Scene       string // "arrival", "encounter" or "crossroads"
+	Description string // Enemy description
+	Recap       string // The run so far
+
+	// Enemy generation
+	Count          int    // Enemies to invent
+	Existing       string // Names of the enemies that already exist
+	GrammarFocuses string // Allowed grammar focus values
+}
+
+// sampleData fills every variable, so validation exercises every branch
+// a template is likely to take
+var sampleData = PromptData{
+	Persona:    "Grumpy Wizard",
+	Tone:       "You are an ancient, slightly grumpy but wise wizard.",
+	Enemy:      "Goblin",
+	Location:   "Dark Forest",
+	Tier:       4,
+	Difficulty: cefr.DefaultLevel,
+	Strictness: "moderate",
+	Structures: "past simple",
+	Vocabulary: "simple words",
+	Options:    "1. The left path\n2. The right path",
+
+	GrammarFocus: "verb tense",
+	PlayerHP:     80,
+	PlayerMaxHP:  100,
+	EnemyHP:      30,
+	EnemyMaxHP:   50,
+	HasHistory:   true,
+	History:      "EARLIER IN THIS FIGHT:\n- Score 7, dealt 10, took 6: The goblin staggers.",
+
+	ExplanationRule: "explanation must be an empty string.",
+	PlayerTextRule:  PlayerTextRule,
+
+	Scene:       "crossroads",
+	Description: "A sneaky goblin with a rusty dagger.",
+	Recap:       "Enemies defeated so far: 1.",
+
+	Count:          3,
+	Existing:       "Goblin, Troll",
+	GrammarFocuses: "verb tense, articles",
+}
+
+// Prompt is a parsed prompt template
+type Prompt struct {
+	Name    string
+	Version string // From the version comment, "unversioned" if missing
+	Source  string // "embedded" or the path of the override file
+	tmpl    *template.Template
+}
+
+// Tag identifies the template for the logs, e.g. "combat@2" or
+// "combat@3 (custom)"
+func (p *Prompt) Tag() string {
+	if p.Source == "embedded" {
+		return p.Name + "@" + p.Version
+	}
+	return p.Name + "@" + p.Version + " (custom)"
+}
+
+// Render executes the template with data
+func (p *Prompt) Render(data PromptData) (string, error) {
+	var buf bytes.Buffer
+	if err := p.tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+var (
+	promptsMu sync.RWMutex
+	prompts   = map[string]*Prompt{}
+)
+
+func init() {
+	entries, err := embeddedPrompts.ReadDir("prompts")
+	if err != nil {
+		panic(err)
+	}
+	for _, e := range entries {
+		data, err := embeddedPrompts.ReadFile("prompts/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		p, err := parsePrompt(strings.TrimSuffix(e.Name(), ".tmpl"), "embedded", string(data))
+		if err != nil {
+			panic(err)
+		}
+		prompts[p.Name] = p
+	}
+}
+
+// parsePrompt parses a template and checks that it renders
+func parsePrompt(name, source, text string) (*Prompt, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt %s (%s): %w", name, source, err)
+	}
+
+	p := &Prompt{Name: name, Version: "unversioned", Source: source, tmpl: tmpl}
+	if m := versionTag.FindStringSubmatch(text); m != nil {
+		p.Version = m[1]
+	}
+
+	out, err := p.Render(sampleData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render prompt %s (%s): %w", name, source, err)
+	}
+	if strings.TrimSpace(out) == "" {
+		return nil, fmt.Errorf("prompt %s (%s) renders to an empty string", name, source)
+	}
+	return p, nil
+}
+
+// LoadPromptDir loads <name>.tmpl files from dir that override the
+// embedded prompts. Every override must parse and render before it
+// replaces a prompt; on error no prompt is replaced. A missing directory
+// is not an error.
+func LoadPromptDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return err
+	}
+
+	loaded := map[string]*Prompt{}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+		if GetPrompt(name) == nil {
+			log.Printf("Ignoring unknown prompt template %s", path)
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		p, err := parsePrompt(name, path, string(data))
+		if err != nil {
+			return err
+		}
+		loaded[name] = p
+	}
+
+	promptsMu.Lock()
+	defer promptsMu.Unlock()
+	for name, p := range loaded {
+		prompts[name] = p
+	}
+	return nil
+}
+
+// GetPrompt returns the prompt template in use for name, or nil
+func GetPrompt(name string) *Prompt {
+	promptsMu.RLock()
+	defer promptsMu.RUnlock()
+	return prompts[name]
+}
+
+// Prompts returns every prompt template in use, sorted by name
+func Prompts() []*Prompt {
+	promptsMu.RLock()
+	defer promptsMu.RUnlock()
+	list := make([]*Prompt, 0, len(prompts))
+	for _, p := range prompts {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// renderPrompt renders the prompt template in use for name, in the voice
+// of the persona with the given ID
+func renderPrompt(name, persona string, data PromptData) (string, *Prompt, error) {
+	p := GetPrompt(name)
+	if p == nil {
+		return "", nil, fmt.Errorf("unknown prompt %s", name)
+	}
+	dm := GetPersona(persona)
+	data.Persona = dm.Name
+	data.Tone = dm.Tone
+	data.PlayerTextRule = PlayerTextRule
+	out, err := p.Render(data)
+	return out, p, err
+}
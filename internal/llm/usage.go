@@ -0,0 +1,235 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erwaen/type-glish/internal/config"
+)
+
+const usageFileName = "usage.json"
+
+// usageDays is how many days of daily usage are kept
+const usageDays = 30
+
+// Usage counts the tokens of one or more calls
+type Usage struct {
+	Calls            int `json:"calls"`
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"` // Includes thinking tokens, which are billed as output
+}
+
+// Total returns prompt plus completion tokens
+func (u Usage) Total() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// Add returns the sum of two usages
+func (u Usage) Add(o Usage) Usage {
+	return Usage{
+		Calls:            u.Calls + o.Calls,
+		PromptTokens:     u.PromptTokens + o.PromptTokens,
+		CompletionTokens: u.CompletionTokens + o.CompletionTokens,
+	}
+}
+
+// Cost returns the price of the usage in USD, given prices per million
+// prompt and completion tokens
+func (u Usage) Cost(inputPerM, outputPerM float64) float64 {
+	return (float64(u.PromptTokens)*inputPerM + float64(u.CompletionTokens)*outputPerM) / 1e6
+}
+
+// UsageReporter is implemented by providers that know the token usage of
+// their last call
+type UsageReporter interface {
+	LastUsage() Usage
+}
+
+// UsageLedger aggregates token usage per provider for the session, per
+// day and for the lifetime of the install. Daily and lifetime totals are
+// persisted in the config directory.
+type UsageLedger struct {
+	mu       sync.Mutex
+	Lifetime map[string]Usage            `json:"lifetime"`
+	Daily    map[string]map[string]Usage `json:"daily"` // Date (YYYY-MM-DD) to provider to usage
+	session  map[string]Usage
+}
+
+// LoadUsage reads the usage ledger, or returns an empty one
+func LoadUsage() (*UsageLedger, error) {
+	l := &UsageLedger{
+		Lifetime: map[string]Usage{},
+		Daily:    map[string]map[string]Usage{},
+		session:  map[string]Usage{},
+	}
+
+	path, err := config.GetDataPath(usageFileName)
+	if err != nil {
+		return l, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+		return l, err
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return l, fmt.Errorf("failed to parse usage: %w", err)
+	}
+	if l.Lifetime == nil {
+		l.Lifetime = map[string]Usage{}
+	}
+	if l.Daily == nil {
+		l.Daily = map[string]map[string]Usage{}
+	}
+	return l, nil
+}
+
+func today() string {
+	return time.Now().Format(time.DateOnly)
+}
+
+// Record adds the usage of a call and saves the ledger
+func (l *UsageLedger) Record(provider string, u Usage) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.session[provider] = l.session[provider].Add(u)
+	l.Lifetime[provider] = l.Lifetime[provider].Add(u)
+
+	day := today()
+	if l.Daily[day] == nil {
+		l.Daily[day] = map[string]Usage{}
+	}
+	l.Daily[day][provider] = l.Daily[day][provider].Add(u)
+
+	// Forget old days
+	cutoff := time.Now().AddDate(0, 0, -usageDays).Format(time.DateOnly)
+	for d := range l.Daily {
+		if d < cutoff {
+			delete(l.Daily, d)
+		}
+	}
+
+	if err := l.save(); err != nil {
+		log.Printf("Error saving usage: %v", err)
+	}
+}
+
+func (l *UsageLedger) save() error {
+	path, err := config.GetDataPath(usageFileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func copyUsage(m map[string]Usage) map[string]Usage {
+	c := make(map[string]Usage, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// Session returns the usage since the game started, per provider
+func (l *UsageLedger) Session() map[string]Usage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return copyUsage(l.session)
+}
+
+// Today returns today's usage, per provider
+func (l *UsageLedger) Today() map[string]Usage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return copyUsage(l.Daily[today()])
+}
+
+// LifetimeUsage returns the usage since the ledger was created, per provider
+func (l *UsageLedger) LifetimeUsage() map[string]Usage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return copyUsage(l.Lifetime)
+}
+
+// ProviderNames returns every provider in the ledger, sorted
+func (l *UsageLedger) ProviderNames() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var names []string
+	for name := range l.Lifetime {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsPaid reports whether calls to the provider cost money
+func IsPaid(provider string) bool {
+	return strings.HasPrefix(provider, "gemini")
+}
+
+// MeteredProvider records the token usage of a provider and, once the
+// daily token budget is spent, sends calls to a fallback provider instead
+type MeteredProvider struct {
+	next     Provider
+	fallback Provider // Used when over budget, may be nil
+	ledger   *UsageLedger
+	budget   int // Daily token budget of next, 0 for none
+}
+
+// NewMeteredProvider meters next in the ledger. budget is a daily token
+// limit, 0 for none.
+func NewMeteredProvider(next, fallback Provider, ledger *UsageLedger, budget int) *MeteredProvider {
+	return &MeteredProvider{next: next, fallback: fallback, ledger: ledger, budget: budget}
+}
+
+// OverBudget reports whether today's usage of the main provider reached
+// the budget
+func (p *MeteredProvider) OverBudget() bool {
+	return p.budget > 0 && p.ledger.Today()[p.next.Name()].Total() >= p.budget
+}
+
+// active returns the provider that serves the next call
+func (p *MeteredProvider) active() Provider {
+	if p.fallback != nil && p.OverBudget() {
+		return p.fallback
+	}
+	return p.next
+}
+
+func (p *MeteredProvider) Name() string {
+	return p.active().Name()
+}
+
+func (p *MeteredProvider) Call(messages []ChatMessage) (string, error) {
+	provider := p.active()
+	if provider != p.next {
+		log.Printf("Daily token budget of %s spent, using %s", p.next.Name(), provider.Name())
+	}
+
+	resp, err := provider.Call(messages)
+	if err != nil {
+		return "", err
+	}
+
+	u := Usage{Calls: 1}
+	if r, ok := provider.(UsageReporter); ok {
+		u = r.LastUsage()
+		u.Calls = 1
+	}
+	p.ledger.Record(provider.Name(), u)
+	return resp, nil
+}
@@ -0,0 +1,446 @@
+package offline
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/erwaen/type-glish/internal/llm"
+	"github.com/erwaen/type-glish/internal/textsim"
+)
+
+// The offline grader is a rule-based stand-in for the LLM, used when the
+// provider is unreachable. It fills the same assessment structs so the
+// rules engine and result screens work unchanged.
+
+//go:embed words.txt
+var wordList string
+
+var dictionary = sync.OnceValue(func() map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.Fields(wordList) {
+		words[w] = true
+	}
+	return words
+})
+
+// Error categories, matching the ones the LLM is asked to use
+const (
+	categorySpelling       = "spelling"
+	categoryAgreement      = "agreement"
+	categoryArticles       = "articles"
+	categoryCapitalization = "capitalization"
+	categoryPunctuation    = "punctuation"
+	categoryNone           = "none"
+)
+
+// categoryOrder ranks categories when picking the main mistake
+var categoryOrder = []string{categorySpelling, categoryAgreement, categoryArticles, categoryCapitalization, categoryPunctuation}
+
+// penalties is the score lost per mistake of each category
+var penalties = map[string]float64{
+	categorySpelling:       1.5,
+	categoryAgreement:      1.5,
+	categoryArticles:       1,
+	categoryCapitalization: 0.5,
+	categoryPunctuation:    0.5,
+}
+
+// agreementFixes corrects common subject-verb pairs
+var agreementFixes = map[[2]string]string{
+	{"i", "is"}: "am", {"i", "are"}: "am", {"i", "has"}: "have", {"i", "does"}: "do",
+	{"you", "is"}: "are", {"you", "am"}: "are", {"you", "was"}: "were", {"you", "has"}: "have", {"you", "does"}: "do",
+	{"we", "is"}: "are", {"we", "am"}: "are", {"we", "was"}: "were", {"we", "has"}: "have", {"we", "does"}: "do",
+	{"they", "is"}: "are", {"they", "am"}: "are", {"they", "was"}: "were", {"they", "has"}: "have", {"they", "does"}: "do",
+	{"he", "are"}: "is", {"he", "am"}: "is", {"he", "have"}: "has", {"he", "do"}: "does", {"he", "don't"}: "doesn't",
+	{"she", "are"}: "is", {"she", "am"}: "is", {"she", "have"}: "has", {"she", "do"}: "does", {"she", "don't"}: "doesn't",
+	{"it", "are"}: "is", {"it", "am"}: "is", {"it", "have"}: "has", {"it", "do"}: "does", {"it", "don't"}: "doesn't",
+}
+
+// presentVerbs are base forms that need an -s after he, she or it. Verbs
+// whose past tense looks like the base form (hit, cut, cast...) are left
+// out on purpose.
+var presentVerbs = map[string]bool{
+	"attack": true, "strike": true, "swing": true, "slash": true, "stab": true, "block": true,
+	"dodge": true, "charge": true, "kick": true, "punch": true, "grab": true, "throw": true,
+	"fight": true, "run": true, "jump": true, "go": true, "try": true, "want": true, "need": true,
+	"like": true, "see": true, "look": true, "use": true, "take": true, "make": true, "move": true,
+	"walk": true, "climb": true, "shoot": true, "fly": true, "bite": true, "roar": true,
+	"scream": true, "laugh": true, "fall": true, "die": true, "break": true, "hide": true,
+}
+
+// Sounds that break the a/an letter rule
+var (
+	consonantSoundPrefixes = []string{"uni", "use", "usu", "uti", "eu", "one", "once", "ur"}
+	silentH                = []string{"hour", "honest", "honor", "honour", "heir"}
+)
+
+// token is one whitespace-separated field split into its word and the
+// punctuation around it
+type token struct {
+	prefix, word, suffix string
+}
+
+func (t token) String() string {
+	return t.prefix + t.word + t.suffix
+}
+
+// report is the result of running every check on a sentence
+type report struct {
+	corrected string
+	mistakes  map[string]int
+	words     int
+	variety   float64 // Distinct words over total words
+}
+
+func splitToken(field string) token {
+	runes := []rune(field)
+	start, end := 0, len(runes)
+	for start < end && !isWordRune(runes[start]) {
+		start++
+	}
+	for end > start && !isWordRune(runes[end-1]) {
+		end--
+	}
+	return token{prefix: string(runes[:start]), word: string(runes[start:end]), suffix: string(runes[end:])}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// known reports whether a word, or the stem of an inflected form, is in
+// the dictionary
+func known(word string) bool {
+	dict := dictionary()
+	if dict[word] {
+		return true
+	}
+	for _, suffix := range []string{"'s", "s'", "n't", "'ll", "'re", "'ve", "'d", "'m"} {
+		if base, ok := strings.CutSuffix(word, suffix); ok && dict[base] {
+			return true
+		}
+	}
+	for _, rule := range [][2]string{
+		{"ies", "y"}, {"ied", "y"}, {"ier", "y"}, {"iest", "y"}, {"ily", "y"},
+		{"es", ""}, {"s", ""}, {"ed", ""}, {"ed", "e"}, {"d", ""}, {"ing", ""}, {"ing", "e"},
+		{"ly", ""}, {"er", ""}, {"er", "e"}, {"est", ""}, {"est", "e"}, {"ness", ""}, {"ful", ""}, {"less", ""},
+	} {
+		base, ok := strings.CutSuffix(word, rule[0])
+		if !ok || base == "" {
+			continue
+		}
+		if dict[base+rule[1]] {
+			return true
+		}
+		// Doubled consonant: stopped, hitting, bigger
+		if n := len(base); rule[1] == "" && n > 2 && base[n-1] == base[n-2] && dict[base[:n-1]] {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest returns the closest dictionary word within two edits, or ""
+func suggest(word string) string {
+	best, bestDist := "", 3
+	for w := range dictionary() {
+		if w[0] != word[0] || abs(len(w)-len(word)) >= bestDist {
+			continue
+		}
+		if d := textsim.OSADistance(word, w); d < bestDist || (d == bestDist && w < best) {
+			best, bestDist = w, d
+		}
+	}
+	return best
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// matchCase gives a replacement the capitalization of the original word
+func matchCase(original, replacement string) string {
+	r := []rune(original)
+	if len(r) > 0 && unicode.IsUpper(r[0]) {
+		rr := []rune(replacement)
+		rr[0] = unicode.ToUpper(rr[0])
+		return string(rr)
+	}
+	return replacement
+}
+
+// thirdPerson conjugates a base verb for he, she or it
+func thirdPerson(verb string) string {
+	switch {
+	case verb == "go" || verb == "do":
+		return verb + "es"
+	case strings.HasSuffix(verb, "y") && len(verb) > 1 && !strings.ContainsRune("aeiou", rune(verb[len(verb)-2])):
+		return verb[:len(verb)-1] + "ies"
+	case strings.HasSuffix(verb, "s"), strings.HasSuffix(verb, "sh"), strings.HasSuffix(verb, "ch"),
+		strings.HasSuffix(verb, "x"), strings.HasSuffix(verb, "z"):
+		return verb + "es"
+	}
+	return verb + "s"
+}
+
+// wantsAn reports whether a word is read with a leading vowel sound
+func wantsAn(word string) bool {
+	for _, p := range silentH {
+		if strings.HasPrefix(word, p) {
+			return true
+		}
+	}
+	for _, p := range consonantSoundPrefixes {
+		if strings.HasPrefix(word, p) {
+			return false
+		}
+	}
+	return word != "" && strings.ContainsRune("aeiou", rune(word[0]))
+}
+
+// check runs every rule on a sentence and returns the corrected sentence
+// with a count of mistakes per category
+func check(sentence string) report {
+	rep := report{mistakes: make(map[string]int)}
+
+	var tokens []token
+	for _, f := range strings.Fields(sentence) {
+		tokens = append(tokens, splitToken(f))
+	}
+
+	// Spelling and the pronoun "I"
+	for i, t := range tokens {
+		lower := strings.ToLower(t.word)
+		switch {
+		case lower == "i" && t.word != "I":
+			tokens[i].word = "I"
+			rep.mistakes[categoryCapitalization]++
+		case len([]rune(lower)) >= 3 && isLetters(lower) && !known(lower):
+			// Only flag words that look like a typo of a known word, so
+			// rare but correct words are not punished
+			if s := suggest(lower); s != "" {
+				tokens[i].word = matchCase(t.word, s)
+				rep.mistakes[categorySpelling]++
+			}
+		}
+	}
+
+	// Subject-verb agreement and articles look at word pairs
+	for i := 0; i+1 < len(tokens); i++ {
+		a, b := strings.ToLower(tokens[i].word), strings.ToLower(tokens[i+1].word)
+		if tokens[i].suffix != "" {
+			continue // The pair spans punctuation
+		}
+		if fix, ok := agreementFixes[[2]string{a, b}]; ok {
+			tokens[i+1].word = matchCase(tokens[i+1].word, fix)
+			rep.mistakes[categoryAgreement]++
+			continue
+		}
+		if (a == "he" || a == "she" || a == "it") && presentVerbs[b] {
+			tokens[i+1].word = matchCase(tokens[i+1].word, thirdPerson(b))
+			rep.mistakes[categoryAgreement]++
+			continue
+		}
+		if a == "a" && wantsAn(b) {
+			tokens[i].word = matchCase(tokens[i].word, "an")
+			rep.mistakes[categoryArticles]++
+		} else if a == "an" && b != "" && !wantsAn(b) && isLetters(b) {
+			tokens[i].word = matchCase(tokens[i].word, "a")
+			rep.mistakes[categoryArticles]++
+		}
+	}
+
+	if len(tokens) > 0 {
+		// Sentence starts with a capital letter
+		first := []rune(tokens[0].word)
+		if len(first) > 0 && unicode.IsLower(first[0]) {
+			first[0] = unicode.ToUpper(first[0])
+			tokens[0].word = string(first)
+			rep.mistakes[categoryCapitalization]++
+		}
+
+		// Sentence ends with punctuation
+		last := &tokens[len(tokens)-1]
+		if !strings.ContainsAny(last.suffix, ".!?") {
+			last.suffix += "."
+			rep.mistakes[categoryPunctuation]++
+		}
+	}
+
+	parts := make([]string, len(tokens))
+	distinct := make(map[string]bool)
+	for i, t := range tokens {
+		parts[i] = t.String()
+		if t.word != "" {
+			rep.words++
+			distinct[strings.ToLower(t.word)] = true
+		}
+	}
+	rep.corrected = strings.Join(parts, " ")
+	if rep.words > 0 {
+		rep.variety = float64(len(distinct)) / float64(rep.words)
+	}
+	return rep
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && r != '\'' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// score turns a report into a 1-10 grammar score. Mistakes cost points,
+// and short or repetitive sentences cannot reach the top scores.
+func (r report) score() int {
+	score := 10.0
+	for category, n := range r.mistakes {
+		score -= penalties[category] * float64(n)
+	}
+	switch {
+	case r.words < 3:
+		score -= 6
+	case r.words < 5:
+		score -= 2
+	case r.words < 8:
+		score -= 1
+	}
+	if r.words >= 5 && r.variety < 0.7 {
+		score--
+	}
+	return max(1, min(10, int(math.Round(score))))
+}
+
+// mainCategory returns the most frequent mistake category, or "none"
+func (r report) mainCategory() string {
+	best, count := categoryNone, 0
+	for _, c := range categoryOrder {
+		if r.mistakes[c] > count {
+			best, count = c, r.mistakes[c]
+		}
+	}
+	return best
+}
+
+// vocabulary picks up to three longer dictionary words worth remembering
+func (r report) vocabulary() []string {
+	var words []string
+	seen := make(map[string]bool)
+	for _, f := range strings.Fields(r.corrected) {
+		w := strings.ToLower(splitToken(f).word)
+		if len(w) >= 6 && dictionary()[w] && !seen[w] {
+			seen[w] = true
+			words = append(words, w)
+			if len(words) == 3 {
+				break
+			}
+		}
+	}
+	return words
+}
+
+// counterDamage mirrors the counter-attack rule given to the LLM
+func counterDamage(score int) int {
+	switch {
+	case score >= 8:
+		return 4
+	case score >= 5:
+		return 8
+	default:
+		return 13
+	}
+}
+
+// GradeCombat grades a combat action without an LLM
+func GradeCombat(action, enemy string) llm.CombatAssessment {
+	action = llm.SanitizeInput(action)
+	rep := check(action)
+	score := rep.score()
+
+	a := llm.CombatAssessment{
+		CorrectedSentence: rep.corrected,
+		GrammarScore:      score,
+		DamageDealt:       score * 3 / 2,
+		DamageReceived:    counterDamage(score),
+		IsRelevant:        true,
+		ErrorCategory:     rep.mainCategory(),
+		Vocabulary:        rep.vocabulary(),
+		InjectionDetected: llm.DetectInjection(action),
+		Offline:           true,
+	}
+	switch {
+	case score >= 8:
+		a.DMComment = "Hmph. Clean work, and I graded it from memory alone."
+		a.Outcome = fmt.Sprintf("Your attack lands cleanly on the %s.", enemy)
+	case score >= 5:
+		a.DMComment = "Passable. My old rulebook still found a few scratches."
+		a.Outcome = fmt.Sprintf("You hit the %s, but it shrugs off part of the blow.", enemy)
+	default:
+		a.DMComment = "Even my dusty rulebook is weeping. Mind your words."
+		a.Outcome = fmt.Sprintf("Your attack stumbles, and the %s punishes your hesitation.", enemy)
+	}
+	return a
+}
+
+// GradeAction grades a Free Adventure action without an LLM
+func GradeAction(action string) llm.Assessment {
+	action = llm.SanitizeInput(action)
+	rep := check(action)
+	score := rep.score()
+
+	a := llm.Assessment{
+		CorrectedSentence: rep.corrected,
+		GrammarScore:      score,
+		InjectionDetected: llm.DetectInjection(action),
+		Offline:           true,
+	}
+	switch {
+	case score >= 8:
+		a.DMComment = "Clear and correct. Even without my crystal ball, I can tell."
+		a.OutcomeDescription = "Your words ring true, and the world bends a little in your favor."
+	case score >= 5:
+		a.DMComment = "Understandable, if a little bumpy."
+		a.OutcomeDescription = "You manage what you set out to do, though not as gracefully as you hoped."
+	default:
+		a.DMComment = "The world could not quite follow what you meant."
+		a.OutcomeDescription = "Your attempt fizzles, and the Kingdom waits for clearer words."
+	}
+	return a
+}
+
+// GradePath grades a path choice without an LLM
+func GradePath(choice string) llm.PathAssessment {
+	choice = llm.SanitizeInput(choice)
+	rep := check(choice)
+	score := rep.score()
+
+	a := llm.PathAssessment{
+		CorrectedSentence: rep.corrected,
+		GrammarScore:      score,
+		Healing:           score * 2,
+		IsRelevant:        true,
+		InjectionDetected: llm.DetectInjection(choice),
+		Offline:           true,
+	}
+	switch {
+	case score >= 8:
+		a.DMComment = "Well chosen, and well said."
+		a.Outcome = "The path opens onto a quiet glade, and you rest by a clear spring."
+	case score >= 5:
+		a.DMComment = "A fair choice, though your words wobbled."
+		a.Outcome = "You find a sheltered rock to catch your breath."
+	default:
+		a.DMComment = "You chose a path. Your grammar chose another."
+		a.Outcome = "The path is rough, and you rest only briefly."
+	}
+	return a
+}
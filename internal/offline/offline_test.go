@@ -0,0 +1,307 @@
+package offline
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		sentence  string
+		corrected string
+		mistakes  map[string]int
+		category  string
+	}{
+		{
+			name:      "correct sentence",
+			sentence:  "I attack the goblin with my sword.",
+			corrected: "I attack the goblin with my sword.",
+			category:  categoryNone,
+		},
+
+		// Spelling
+		{
+			name:      "typo",
+			sentence:  "I atack the goblin with my sword.",
+			corrected: "I attack the goblin with my sword.",
+			mistakes:  map[string]int{categorySpelling: 1},
+			category:  categorySpelling,
+		},
+		{
+			name:      "doubled letter and swapped letters",
+			sentence:  "I attack the gobblin with my swrod.",
+			corrected: "I attack the goblin with my sword.",
+			mistakes:  map[string]int{categorySpelling: 2},
+			category:  categorySpelling,
+		},
+		{
+			name:      "inflected forms are known",
+			sentence:  "The brave knight carefully draws her shining sword.",
+			corrected: "The brave knight carefully draws her shining sword.",
+			category:  categoryNone,
+		},
+
+		// Capitalization
+		{
+			name:      "lowercase pronoun",
+			sentence:  "i attack the goblin with my sword.",
+			corrected: "I attack the goblin with my sword.",
+			mistakes:  map[string]int{categoryCapitalization: 1},
+			category:  categoryCapitalization,
+		},
+		{
+			name:      "lowercase first word",
+			sentence:  "the knight raises his shield.",
+			corrected: "The knight raises his shield.",
+			mistakes:  map[string]int{categoryCapitalization: 1},
+			category:  categoryCapitalization,
+		},
+
+		// Punctuation
+		{
+			name:      "missing full stop",
+			sentence:  "I attack the goblin with my sword",
+			corrected: "I attack the goblin with my sword.",
+			mistakes:  map[string]int{categoryPunctuation: 1},
+			category:  categoryPunctuation,
+		},
+		{
+			name:      "exclamation mark ends a sentence",
+			sentence:  "I attack the goblin with my sword!",
+			corrected: "I attack the goblin with my sword!",
+			category:  categoryNone,
+		},
+
+		// Articles
+		{
+			name:      "a before a vowel",
+			sentence:  "I throw a axe at the troll.",
+			corrected: "I throw an axe at the troll.",
+			mistakes:  map[string]int{categoryArticles: 1},
+			category:  categoryArticles,
+		},
+		{
+			name:      "an before a consonant",
+			sentence:  "I swing an heavy hammer at the orc.",
+			corrected: "I swing a heavy hammer at the orc.",
+			mistakes:  map[string]int{categoryArticles: 1},
+			category:  categoryArticles,
+		},
+		{
+			name:      "capitalized article",
+			sentence:  "A ugly orc blocks the narrow bridge.",
+			corrected: "An ugly orc blocks the narrow bridge.",
+			mistakes:  map[string]int{categoryArticles: 1},
+			category:  categoryArticles,
+		},
+		{
+			name:      "silent h",
+			sentence:  "I found a honest merchant in town.",
+			corrected: "I found an honest merchant in town.",
+			mistakes:  map[string]int{categoryArticles: 1},
+			category:  categoryArticles,
+		},
+		{
+			name:      "an hour is correct",
+			sentence:  "I wait for an hour by the gate.",
+			corrected: "I wait for an hour by the gate.",
+			category:  categoryNone,
+		},
+		{
+			name:      "a unicorn is correct",
+			sentence:  "I see a unicorn in the forest.",
+			corrected: "I see a unicorn in the forest.",
+			category:  categoryNone,
+		},
+
+		// Subject-verb agreement
+		{
+			name:      "third person without -s",
+			sentence:  "He attack the goblin with his sword.",
+			corrected: "He attacks the goblin with his sword.",
+			mistakes:  map[string]int{categoryAgreement: 1},
+			category:  categoryAgreement,
+		},
+		{
+			name:      "third person of go",
+			sentence:  "He go to the dark tower.",
+			corrected: "He goes to the dark tower.",
+			mistakes:  map[string]int{categoryAgreement: 1},
+			category:  categoryAgreement,
+		},
+		{
+			name:      "she have",
+			sentence:  "She have a magic staff in her hand.",
+			corrected: "She has a magic staff in her hand.",
+			mistakes:  map[string]int{categoryAgreement: 1},
+			category:  categoryAgreement,
+		},
+		{
+			name:      "they is",
+			sentence:  "They is running toward the castle gate.",
+			corrected: "They are running toward the castle gate.",
+			mistakes:  map[string]int{categoryAgreement: 1},
+			category:  categoryAgreement,
+		},
+		{
+			name:      "I is",
+			sentence:  "I is ready to fight the dragon.",
+			corrected: "I am ready to fight the dragon.",
+			mistakes:  map[string]int{categoryAgreement: 1},
+			category:  categoryAgreement,
+		},
+		{
+			name:      "he don't",
+			sentence:  "He don't fear the dark.",
+			corrected: "He doesn't fear the dark.",
+			mistakes:  map[string]int{categoryAgreement: 1},
+			category:  categoryAgreement,
+		},
+		{
+			name:      "past tense that looks like the base form",
+			sentence:  "He cast a spell at the troll.",
+			corrected: "He cast a spell at the troll.",
+			category:  categoryNone,
+		},
+		{
+			name:      "agreement after a comma",
+			sentence:  "I dodge, it bite my arm.",
+			corrected: "I dodge, it bites my arm.",
+			mistakes:  map[string]int{categoryAgreement: 1},
+			category:  categoryAgreement,
+		},
+
+		// Several rules at once
+		{
+			name:      "many mistakes",
+			sentence:  "i atack the gobblin wiht a axe",
+			corrected: "I attack the goblin with an axe.",
+			mistakes: map[string]int{
+				categorySpelling: 3, categoryArticles: 1, categoryCapitalization: 1, categoryPunctuation: 1,
+			},
+			category: categorySpelling,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep := check(tt.sentence)
+			if rep.corrected != tt.corrected {
+				t.Errorf("corrected = %q, want %q", rep.corrected, tt.corrected)
+			}
+			want := tt.mistakes
+			if want == nil {
+				want = map[string]int{}
+			}
+			if !maps.Equal(rep.mistakes, want) {
+				t.Errorf("mistakes = %v, want %v", rep.mistakes, want)
+			}
+			if got := rep.mainCategory(); got != tt.category {
+				t.Errorf("mainCategory = %q, want %q", got, tt.category)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name string
+		rep  report
+		want int
+	}{
+		{"perfect long sentence", report{words: 8, variety: 1}, 10},
+		{"perfect short sentence", report{words: 4, variety: 1}, 8},
+		{"perfect medium sentence", report{words: 6, variety: 1}, 9},
+		{"one word", report{words: 1, variety: 1}, 4},
+		{"repetitive", report{words: 8, variety: 0.5}, 9},
+		{"one spelling mistake", report{words: 8, variety: 1, mistakes: map[string]int{categorySpelling: 1}}, 9},
+		{"minor mistakes", report{words: 8, variety: 1, mistakes: map[string]int{categoryCapitalization: 1, categoryPunctuation: 1}}, 9},
+		{"every category", report{words: 8, variety: 1, mistakes: map[string]int{
+			categorySpelling: 1, categoryAgreement: 1, categoryArticles: 1, categoryCapitalization: 1, categoryPunctuation: 1,
+		}}, 5},
+		{"floor", report{words: 1, variety: 1, mistakes: map[string]int{categorySpelling: 10}}, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.rep.score(); got != tt.want {
+			t.Errorf("%s: score = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGradeRanges(t *testing.T) {
+	sentences := []string{
+		"",
+		"Attack.",
+		"I hit hit hit hit hit hit.",
+		"i atack teh gobblin wiht a axe",
+		"I attack the goblin with my sword.",
+		"The brave knight carefully draws her shining sword and charges the enormous dragon.",
+	}
+	for _, s := range sentences {
+		c := GradeCombat(s, "Goblin")
+		if c.GrammarScore < 1 || c.GrammarScore > 10 {
+			t.Errorf("GradeCombat(%q) score = %d, want 1-10", s, c.GrammarScore)
+		}
+		if c.DamageDealt != c.GrammarScore*3/2 || c.DamageReceived != counterDamage(c.GrammarScore) {
+			t.Errorf("GradeCombat(%q) damage = %d/%d for score %d", s, c.DamageDealt, c.DamageReceived, c.GrammarScore)
+		}
+		if !c.Offline || !c.IsRelevant || c.DMComment == "" || c.Outcome == "" {
+			t.Errorf("GradeCombat(%q) = %+v, want a complete offline assessment", s, c)
+		}
+
+		a := GradeAction(s)
+		if a.GrammarScore != c.GrammarScore || !a.Offline || a.OutcomeDescription == "" {
+			t.Errorf("GradeAction(%q) = %+v, want score %d", s, a, c.GrammarScore)
+		}
+
+		p := GradePath(s)
+		if p.GrammarScore != c.GrammarScore || p.Healing != p.GrammarScore*2 || !p.Offline {
+			t.Errorf("GradePath(%q) = %+v, want score %d", s, p, c.GrammarScore)
+		}
+	}
+
+	if got := GradeCombat("The brave knight carefully draws her shining sword and charges the enormous dragon.", "Troll").GrammarScore; got != 10 {
+		t.Errorf("clean long sentence scored %d, want 10", got)
+	}
+	if got := GradeCombat("i atack teh gobblin wiht a axe", "Troll").GrammarScore; got > 4 {
+		t.Errorf("sentence full of mistakes scored %d, want 4 or less", got)
+	}
+}
+
+func TestCounterDamage(t *testing.T) {
+	tests := []struct{ score, want int }{
+		{1, 13}, {4, 13}, {5, 8}, {7, 8}, {8, 4}, {10, 4},
+	}
+	for _, tt := range tests {
+		if got := counterDamage(tt.score); got != tt.want {
+			t.Errorf("counterDamage(%d) = %d, want %d", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestThirdPerson(t *testing.T) {
+	tests := []struct{ verb, want string }{
+		{"attack", "attacks"}, {"go", "goes"}, {"do", "does"}, {"try", "tries"}, {"fly", "flies"},
+		{"slash", "slashes"}, {"punch", "punches"}, {"play", "plays"}, {"kiss", "kisses"},
+	}
+	for _, tt := range tests {
+		if got := thirdPerson(tt.verb); got != tt.want {
+			t.Errorf("thirdPerson(%q) = %q, want %q", tt.verb, got, tt.want)
+		}
+	}
+}
+
+func TestWantsAn(t *testing.T) {
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"axe", true}, {"orc", true}, {"hour", true}, {"honest", true},
+		{"sword", false}, {"unicorn", false}, {"useful", false}, {"one", false}, {"european", false}, {"", false},
+	}
+	for _, tt := range tests {
+		if got := wantsAn(tt.word); got != tt.want {
+			t.Errorf("wantsAn(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+}
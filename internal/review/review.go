@@ -0,0 +1,173 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/erwaen/type-glish/internal/config"
+	"github.com/erwaen/type-glish/internal/textsim"
+)
+
+const deckFileName = "review-cards.json"
+
+const (
+	defaultEase = 2.5
+	minEase     = 1.3
+)
+
+// Card is a mistake the player made, to be retyped correctly later
+type Card struct {
+	Original    string    `json:"original"`  // What the player typed
+	Corrected   string    `json:"corrected"` // What the DM corrected it to
+	Category    string    `json:"category,omitempty"`
+	Comment     string    `json:"explanation,omitempty"`         // The DM comment on the mistake
+	Explanation string    `json:"grammar_explanation,omitempty"` // Grammar explanation in the learner's native language, if any
+	Ease        float64   `json:"ease"`
+	Interval    int       `json:"interval"` // Days until the next review
+	Repetitions int       `json:"repetitions"`
+	Lapses      int       `json:"lapses"`
+	Due         time.Time `json:"due"`
+	Created     time.Time `json:"created"`
+}
+
+// Review schedules the card with the SM-2 algorithm.
+// Quality goes from 0 (blackout) to 5 (perfect recall).
+func (c *Card) Review(quality int, now time.Time) {
+	quality = max(0, min(5, quality))
+
+	if quality < 3 {
+		c.Repetitions = 0
+		c.Interval = 1
+		c.Lapses++
+	} else {
+		switch c.Repetitions {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.Ease))
+		}
+		c.Repetitions++
+	}
+
+	q := float64(5 - quality)
+	c.Ease += 0.1 - q*(0.08+q*0.02)
+	if c.Ease < minEase {
+		c.Ease = minEase
+	}
+
+	c.Due = now.AddDate(0, 0, c.Interval)
+}
+
+// Grade turns a retyped answer into an SM-2 quality for the card
+func Grade(answer string, c *Card) int {
+	answer = textsim.Normalize(answer)
+	want := textsim.Normalize(c.Corrected)
+
+	switch {
+	case answer == "":
+		return 0
+	case answer == want:
+		return 5
+	case textsim.StripPunctuation(answer) == textsim.StripPunctuation(want):
+		return 4
+	}
+
+	similarity := textsim.Similarity(answer, want)
+	switch {
+	case similarity >= 0.9:
+		return 3
+	case similarity >= 0.7:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Deck is the persisted collection of review cards
+type Deck struct {
+	Cards []*Card `json:"cards"`
+}
+
+// LoadDeck reads the deck from the config directory, returning an empty
+// deck if none has been saved yet
+func LoadDeck() (*Deck, error) {
+	path, err := config.GetDataPath(deckFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Deck{}, nil
+		}
+		return nil, err
+	}
+
+	var deck Deck
+	if err := json.Unmarshal(data, &deck); err != nil {
+		return nil, fmt.Errorf("failed to parse review deck: %w", err)
+	}
+	return &deck, nil
+}
+
+// Save writes the deck to the config directory
+func (d *Deck) Save() error {
+	path, err := config.GetDataPath(deckFileName)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal review deck: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// Add stores a correction as a new card, due right away. Only the
+// Original, Corrected, Category, Comment and Explanation fields of the given card
+// are used. It returns the existing card instead if the same mistake was
+// already collected.
+func (d *Deck) Add(correction Card, now time.Time) *Card {
+	key := textsim.Normalize(correction.Original)
+	for _, c := range d.Cards {
+		if textsim.Normalize(c.Original) == key {
+			return c
+		}
+	}
+
+	card := &Card{
+		Original:    correction.Original,
+		Corrected:   correction.Corrected,
+		Category:    correction.Category,
+		Comment:     correction.Comment,
+		Explanation: correction.Explanation,
+		Ease:        defaultEase,
+		Due:         now,
+		Created:     now,
+	}
+	d.Cards = append(d.Cards, card)
+	return card
+}
+
+// Due returns the cards due for review, most overdue first
+func (d *Deck) Due(now time.Time) []*Card {
+	var due []*Card
+	for _, c := range d.Cards {
+		if !c.Due.After(now) {
+			due = append(due, c)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].Due.Before(due[j].Due)
+	})
+	return due
+}
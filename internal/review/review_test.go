@@ -0,0 +1,145 @@
+package review
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestReviewSchedule(t *testing.T) {
+	tests := []struct {
+		name      string
+		qualities []int
+		intervals []int
+		ease      float64
+		reps      int
+		lapses    int
+	}{
+		{
+			name:      "perfect recalls grow the interval",
+			qualities: []int{5, 5, 5},
+			intervals: []int{1, 6, 16},
+			ease:      2.8,
+			reps:      3,
+		},
+		{
+			name:      "good recall keeps the ease",
+			qualities: []int{4, 4, 4, 4},
+			intervals: []int{1, 6, 15, 38},
+			ease:      2.5,
+			reps:      4,
+		},
+		{
+			name:      "hard recall lowers the ease",
+			qualities: []int{3, 3, 3},
+			intervals: []int{1, 6, 13},
+			ease:      2.08,
+			reps:      3,
+		},
+		{
+			name:      "failed recall resets the repetitions",
+			qualities: []int{5, 5, 5, 2, 5},
+			intervals: []int{1, 6, 16, 1, 1},
+			ease:      2.58,
+			reps:      1,
+			lapses:    1,
+		},
+		{
+			name:      "ease never drops below the floor",
+			qualities: []int{0, 0, 0, 0, 1},
+			intervals: []int{1, 1, 1, 1, 1},
+			ease:      minEase,
+			lapses:    5,
+		},
+		{
+			name:      "qualities are clamped to 0-5",
+			qualities: []int{9, -3},
+			intervals: []int{1, 1},
+			ease:      1.8,
+			lapses:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Card{Ease: defaultEase}
+			day := now
+			for i, q := range tt.qualities {
+				c.Review(q, day)
+				if c.Interval != tt.intervals[i] {
+					t.Fatalf("review %d: Interval = %d, want %d", i+1, c.Interval, tt.intervals[i])
+				}
+				if want := day.AddDate(0, 0, c.Interval); !c.Due.Equal(want) {
+					t.Fatalf("review %d: Due = %v, want %v", i+1, c.Due, want)
+				}
+				day = c.Due
+			}
+			if math.Abs(c.Ease-tt.ease) > 1e-9 {
+				t.Errorf("Ease = %.3f, want %.3f", c.Ease, tt.ease)
+			}
+			if c.Repetitions != tt.reps {
+				t.Errorf("Repetitions = %d, want %d", c.Repetitions, tt.reps)
+			}
+			if c.Lapses != tt.lapses {
+				t.Errorf("Lapses = %d, want %d", c.Lapses, tt.lapses)
+			}
+		})
+	}
+}
+
+func TestGrade(t *testing.T) {
+	c := &Card{Corrected: "I went to the market yesterday."}
+	tests := []struct {
+		answer string
+		want   int
+	}{
+		{answer: "", want: 0},
+		{answer: "   ", want: 0},
+		{answer: "I went to the market yesterday.", want: 5},
+		{answer: "  i WENT to the market yesterday. ", want: 5},
+		{answer: "I went to the market yesterday", want: 4},
+		{answer: "I went to the markt yesterday.", want: 3},
+		{answer: "I go to the market yesterday.", want: 2},
+		{answer: "The dragon sleeps.", want: 1},
+	}
+	for _, tt := range tests {
+		if got := Grade(tt.answer, c); got != tt.want {
+			t.Errorf("Grade(%q) = %d, want %d", tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestDeckAdd(t *testing.T) {
+	var d Deck
+	first := d.Add(Card{Original: "I goed home.", Corrected: "I went home.", Comment: "Irregular verb!"}, now)
+	if first.Ease != defaultEase || !first.Due.Equal(now) || !first.Created.Equal(now) {
+		t.Errorf("new card = %+v, want default ease and due now", first)
+	}
+	if first.Comment != "Irregular verb!" {
+		t.Errorf("Comment = %q, want it kept", first.Comment)
+	}
+
+	again := d.Add(Card{Original: "i goed HOME.", Corrected: "I went home."}, now.Add(time.Hour))
+	if again != first || len(d.Cards) != 1 {
+		t.Errorf("same mistake added twice, deck has %d cards", len(d.Cards))
+	}
+}
+
+func TestDeckDue(t *testing.T) {
+	d := Deck{Cards: []*Card{
+		{Original: "later", Due: now.Add(time.Hour)},
+		{Original: "today", Due: now},
+		{Original: "oldest", Due: now.AddDate(0, 0, -3)},
+		{Original: "yesterday", Due: now.AddDate(0, 0, -1)},
+	}}
+	var got []string
+	for _, c := range d.Due(now) {
+		got = append(got, c.Original)
+	}
+	want := []string{"oldest", "yesterday", "today"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Due = %q, want %q", got, want)
+	}
+}
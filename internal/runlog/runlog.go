@@ -0,0 +1,138 @@
+package runlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/erwaen/type-glish/internal/config"
+)
+
+const runsDirName = "runs"
+
+// Turn kinds
+const (
+	KindCombat      = "combat"
+	KindPath        = "path"
+	KindReview      = "review"
+	KindQuickStrike = "quick_strike"
+)
+
+// Run results
+const (
+	ResultDefeat = "defeat"
+)
+
+// Turn is one graded player action
+type Turn struct {
+	Time           time.Time `json:"time"`
+	Kind           string    `json:"kind"`
+	Enemy          string    `json:"enemy,omitempty"`
+	Input          string    `json:"input"`
+	Corrected      string    `json:"corrected,omitempty"`
+	Score          int       `json:"score"`
+	DamageDealt    int       `json:"damage_dealt"`
+	DamageReceived int       `json:"damage_received"`
+	Healing        int       `json:"healing,omitempty"`
+	DMComment      string    `json:"dm_comment,omitempty"`
+	Outcome        string    `json:"outcome,omitempty"`
+	Prompt         string    `json:"prompt,omitempty"` // Prompt template tag, e.g. "combat@2"
+	Combo          int       `json:"combo,omitempty"`  // Combo after this turn
+	Critical       bool      `json:"critical,omitempty"`
+}
+
+// Run is the log of one game, from Start Game until death or quitting
+type Run struct {
+	ID              string    `json:"id"`
+	Started         time.Time `json:"started"`
+	Ended           time.Time `json:"ended,omitzero"`
+	Difficulty      string    `json:"difficulty"`
+	Result          string    `json:"result,omitempty"` // Empty while in progress or if abandoned
+	EnemiesDefeated int       `json:"enemies_defeated"`
+	BestCombo       int       `json:"best_combo"`
+	Criticals       int       `json:"criticals"`
+	Turns           []Turn    `json:"turns"`
+}
+
+// NewRun starts a run log. The ID names the file, so it carries
+// milliseconds and a random suffix: two runs started in the same second
+// must not overwrite each other.
+func NewRun(difficulty string, now time.Time) *Run {
+	return &Run{
+		ID:         fmt.Sprintf("%s-%04x", now.Format("20060102-150405.000"), rand.Intn(0x10000)),
+		Started:    now,
+		Difficulty: difficulty,
+	}
+}
+
+// Add appends a turn to the log
+func (r *Run) Add(t Turn) {
+	r.Turns = append(r.Turns, t)
+}
+
+// End marks the run as finished with the given result
+func (r *Run) End(result string, now time.Time) {
+	r.Result = result
+	r.Ended = now
+}
+
+func runsDir() (string, error) {
+	dir, err := config.GetDataPath(runsDirName)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// Save writes the run to the runs folder in the config directory
+func (r *Run) Save() error {
+	dir, err := runsDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(dir, r.ID+".json"), data, 0644)
+}
+
+// LoadRuns reads every saved run, newest first. Unreadable files are skipped.
+func LoadRuns() ([]*Run, error) {
+	dir, err := runsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []*Run
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		var run Run
+		if err := json.Unmarshal(data, &run); err != nil {
+			continue
+		}
+		runs = append(runs, &run)
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].Started.After(runs[j].Started)
+	})
+	return runs, nil
+}
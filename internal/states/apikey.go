@@ -5,6 +5,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
 	"github.com/erwaen/type-glish/internal/ui"
 )
 
@@ -15,7 +16,7 @@ type APIInputState struct {
 
 func NewAPIInputState(cfg *config.Config) *APIInputState {
 	ti := textinput.New()
-	ti.Placeholder = "Enter your Gemini API Key"
+	ti.Placeholder = i18n.T("apikey.placeholder")
 	ti.Focus()
 	ti.CharLimit = 200
 	ti.Width = 60
@@ -71,6 +72,6 @@ func (s *APIInputState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.C
 }
 
 func (s *APIInputState) View(ctx *game.Context) string {
-	content := "To use Gemini, we need an API Key.\nIt will be saved locally.\n\n" + s.textInput.View()
-	return ui.CenteredView("GEMINI SETUP", content, true, ctx.Width, ctx.Height)
+	content := i18n.T("apikey.intro") + "\n\n" + s.textInput.View()
+	return ui.CenteredView(i18n.T("apikey.title"), content, true, ctx.Width, ctx.Height)
 }
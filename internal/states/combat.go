@@ -1,25 +1,33 @@
 package states
 
 import (
+	"math"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
 	"github.com/erwaen/type-glish/internal/ui"
 )
 
 type CombatState struct {
 	textInput textinput.Model
+	cfg       *config.Config
 }
 
-func NewCombatState() *CombatState {
+func NewCombatState(cfg *config.Config) *CombatState {
 	ti := textinput.New()
-	ti.Placeholder = "Describe your attack..."
+	ti.Placeholder = i18n.T("combat.placeholder")
 	ti.Focus()
 	ti.CharLimit = 200
 	ti.Width = 50
 
 	return &CombatState{
 		textInput: ti,
+		cfg:       cfg,
 	}
 }
 
@@ -36,12 +44,17 @@ func (s *CombatState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd
 		case tea.KeyEnter:
 			if s.textInput.Value() != "" {
 				ctx.LastInput = s.textInput.Value()
-				return &CombatProcessingState{}, nil
+				return &CombatProcessingState{cfg: s.cfg}, nil
+			}
+		case tea.KeyTab:
+			if !ctx.QuickStrikeUsed {
+				ctx.QuickStrikeUsed = true
+				return NewQuickStrikeState(s.cfg), nil
 			}
 		case tea.KeyCtrlC:
 			return s, tea.Quit
 		case tea.KeyEsc:
-			return NewMenuState(nil), nil
+			return NewMenuState(s.cfg), nil
 		}
 	}
 
@@ -51,7 +64,7 @@ func (s *CombatState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd
 
 func (s *CombatState) View(ctx *game.Context) string {
 	if ctx.CurrentEnemy == nil {
-		return ui.CenteredView("ERROR", "No enemy found!", true, ctx.Width, ctx.Height)
+		return ui.CenteredView(i18n.T("common.error"), i18n.T("combat.no_enemy"), true, ctx.Width, ctx.Height)
 	}
 
 	enemy := ctx.CurrentEnemy
@@ -59,17 +72,31 @@ func (s *CombatState) View(ctx *game.Context) string {
 	// Build combat view
 	var content string
 
-	// Status bar at top
-	content += ui.RenderStatusBar(ctx.Stats.HP, 100, ctx.Stats.Gold, ctx.Stats.XP) + "\n\n"
+	// Status bar at top, with the combo once it builds
+	content += ui.RenderStatusBar(ctx.Stats.HP, 100, ctx.Stats.Gold, ctx.Stats.XP) + "\n"
+	if ctx.Combo > 0 {
+		comboStyle := lipgloss.NewStyle().Foreground(ui.ColorPrimary).Bold(true)
+		percent := int(math.Round((game.ComboMultiplier(ctx.Combo) - 1) * 100))
+		content += comboStyle.Render(i18n.T("combat.combo", ctx.Combo, percent)) + "\n"
+	}
+	content += "\n"
 
 	// Header: Location and Enemy
 	content += ui.RenderCombatHeader(enemy.Location, enemy.Name) + "\n\n"
 
-	// Enemy HP Bar
-	content += ui.RenderHPBar(enemy.HP, enemy.MaxHP, enemy.Name, 20) + "\n\n"
+	// Enemy HP Bar and status effects
+	content += ui.RenderHPBar(enemy.HP, enemy.MaxHP, enemy.Name, 20) + "\n"
+	content += renderStatusEffects(ctx) + "\n"
 
 	// DM Description
-	content += ui.StyleSubTitle.Render("DM: "+enemy.Description) + "\n\n"
+	content += ui.StyleSubTitle.Render(i18n.T("common.dm")+" "+enemy.Description) + "\n"
+	if enemy.GrammarFocus != "" {
+		content += ui.StyleHelp.Render(i18n.T("combat.focus", enemy.GrammarFocus)) + "\n"
+	}
+	if ab := game.AbilityFor(enemy); ab != nil {
+		content += ui.StyleHelp.Render(i18n.T("combat.ability", ab.Name, ab.Description)) + "\n"
+	}
+	content += "\n"
 
 	// Narrative context if any
 	if ctx.CurrentNarrative != "" {
@@ -80,10 +107,45 @@ func (s *CombatState) View(ctx *game.Context) string {
 	content += "───────────────────────────────────────────\n\n"
 
 	// Input
-	content += "YOUR ACTION:\n"
+	content += i18n.T("combat.your_action") + "\n"
 	content += s.textInput.View() + "\n\n"
 
-	content += ui.StyleHelp.Render("(Type your combat action and press Enter)")
+	help := i18n.T("combat.help")
+	if !ctx.QuickStrikeUsed {
+		help += "\n" + i18n.T("combat.quickstrike_help")
+	}
+	content += ui.StyleHelp.Render(help)
+
+	return ui.CenteredView(i18n.T("combat.title"), content, true, ctx.Width, ctx.Height)
+}
 
-	return ui.CenteredView("COMBAT", content, true, ctx.Width, ctx.Height)
+// renderStatusEffects lists the statuses of the enemy and the player, one
+// line per side that has any
+func renderStatusEffects(ctx *game.Context) string {
+	style := lipgloss.NewStyle().Foreground(ui.ColorWarning)
+
+	var lines string
+	if ctx.CurrentEnemy != nil && len(ctx.EnemyEffects) > 0 {
+		lines += style.Render(i18n.T("status.line", ctx.CurrentEnemy.Name, statusLabels(ctx.EnemyEffects))) + "\n"
+	}
+	if len(ctx.PlayerEffects) > 0 {
+		lines += style.Render(i18n.T("status.line", i18n.T("common.you"), statusLabels(ctx.PlayerEffects))) + "\n"
+	}
+	return lines
+}
+
+// statusLabels renders the statuses of one side
+func statusLabels(effects game.Effects) string {
+	var labels []string
+	for _, e := range effects {
+		switch e.Kind {
+		case game.StatusStunned:
+			labels = append(labels, i18n.T("status.stunned"))
+		case game.StatusPoisoned:
+			labels = append(labels, i18n.T("status.poisoned", e.Power, e.Turns))
+		case game.StatusShielded:
+			labels = append(labels, i18n.T("status.shielded", e.Power))
+		}
+	}
+	return strings.Join(labels, "  ")
 }
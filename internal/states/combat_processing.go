@@ -3,11 +3,17 @@ package states
 import (
 	"fmt"
 	"log"
+	"math"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
 	"github.com/erwaen/type-glish/internal/llm"
+	"github.com/erwaen/type-glish/internal/offline"
+	"github.com/erwaen/type-glish/internal/runlog"
 	"github.com/erwaen/type-glish/internal/ui"
 
 	spinner "github.com/charmbracelet/bubbles/spinner"
@@ -15,6 +21,7 @@ import (
 
 type CombatProcessingState struct {
 	spinner spinner.Model
+	cfg     *config.Config
 }
 
 func (s *CombatProcessingState) Init(ctx *game.Context) tea.Cmd {
@@ -22,17 +29,32 @@ func (s *CombatProcessingState) Init(ctx *game.Context) tea.Cmd {
 	s.spinner.Spinner = spinner.Dot
 	s.spinner.Style = lipgloss.NewStyle().Foreground(ui.ColorPrimary)
 
-	enemyName := "Unknown"
-	location := "Unknown"
+	req := llm.CombatRequest{
+		Action:   ctx.LastInput,
+		Enemy:    "Unknown",
+		Location: "Unknown",
+		Level:    ctx.Level(),
+		Persona:  ctx.Persona,
+
+		ExplanationLanguage: ctx.ExplanationLanguage,
+
+		PlayerHP:    ctx.Stats.HP,
+		PlayerMaxHP: 100,
+		History:     ctx.CombatHistory,
+	}
 	if ctx.CurrentEnemy != nil {
-		enemyName = ctx.CurrentEnemy.Name
-		location = ctx.CurrentEnemy.Location
+		req.Enemy = ctx.CurrentEnemy.Name
+		req.Location = ctx.CurrentEnemy.Location
+		req.Tier = ctx.CurrentEnemy.Tier
+		req.Focus = ctx.CurrentEnemy.GrammarFocus
+		req.EnemyHP = ctx.CurrentEnemy.HP
+		req.EnemyMaxHP = ctx.CurrentEnemy.MaxHP
 	}
 
 	return tea.Batch(
 		s.spinner.Tick,
 		func() tea.Msg {
-			return ctx.LLMClient.AnalyzeCombatAction(ctx.LastInput, enemyName, location)
+			return ctx.LLMClient.AnalyzeCombatAction(req)
 		},
 	)
 }
@@ -44,34 +66,37 @@ func (s *CombatProcessingState) Update(msg tea.Msg, ctx *game.Context) (GameStat
 			log.Printf("Error from LLM: %v", msg.Err)
 			// Store error for display
 			ctx.LastError = msg.Err.Error()
-			// On error, create a default assessment
-			ctx.CombatAssessment = llm.CombatAssessment{
-				CorrectedSentence: ctx.LastInput,
-				GrammarScore:      5,
-				DamageDealt:       7,
-				DamageReceived:    5,
-				DMComment:         "The Dungeon Master is momentarily distracted...",
-				Outcome:           "Your attack connects, but so does the enemy's!",
-				IsRelevant:        true,
+			// Without a provider, grade with the offline rules instead
+			enemy := "enemy"
+			if ctx.CurrentEnemy != nil {
+				enemy = ctx.CurrentEnemy.Name
 			}
+			ctx.CombatAssessment = offline.GradeCombat(ctx.LastInput, enemy)
 		} else {
 			ctx.LastError = "" // Clear any previous error
 			ctx.CombatAssessment = msg.Data
+			ctx.RecordCorrection(ctx.LastInput, msg.Data)
 		}
 
 		// Apply damage
-		if ctx.CurrentEnemy != nil {
-			ctx.CurrentEnemy.HP -= ctx.CombatAssessment.DamageDealt
-			if ctx.CurrentEnemy.HP < 0 {
-				ctx.CurrentEnemy.HP = 0
-			}
-		}
-		ctx.Stats.HP -= ctx.CombatAssessment.DamageReceived
-		if ctx.Stats.HP < 0 {
-			ctx.Stats.HP = 0
-		}
+		ctx.ApplyCombatAssessment(&ctx.CombatAssessment)
+
+		a := ctx.CombatAssessment
+		ctx.RecordTurn(runlog.Turn{
+			Kind:           runlog.KindCombat,
+			Input:          ctx.LastInput,
+			Corrected:      a.CorrectedSentence,
+			Score:          a.GrammarScore,
+			DamageDealt:    a.DamageDealt,
+			DamageReceived: a.DamageReceived,
+			DMComment:      a.DMComment,
+			Outcome:        a.Outcome,
+			Prompt:         a.Prompt,
+			Combo:          ctx.LastStrike.Combo,
+			Critical:       ctx.LastStrike.Critical,
+		})
 
-		return &CombatResultState{}, nil
+		return &CombatResultState{cfg: s.cfg}, nil
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -89,6 +114,25 @@ func (s *CombatProcessingState) Update(msg tea.Msg, ctx *game.Context) (GameStat
 
 func (s *CombatProcessingState) View(ctx *game.Context) string {
 	spin := s.spinner.View()
-	content := fmt.Sprintf("%s The Dungeon Master judges your attack...", spin)
-	return ui.CenteredView("⚔ COMBAT ⚔", content, true, ctx.Width, ctx.Height)
+	content := fmt.Sprintf("%s %s", spin, i18n.T("combat.processing"))
+	content += renderRetryStatus(ctx)
+	return ui.CenteredView(i18n.T("combat.processing_title"), content, true, ctx.Width, ctx.Height)
+}
+
+// renderRetryStatus shows a retry or rate limit wait under a spinner
+func renderRetryStatus(ctx *game.Context) string {
+	if ctx.Retry == nil {
+		return ""
+	}
+	st, ok := ctx.Retry.Status()
+	if !ok {
+		return ""
+	}
+
+	secs := max(0, int(math.Ceil(time.Until(st.Until).Seconds())))
+	text := i18n.T("llm.retrying", st.Reason, secs, st.Attempt, st.MaxAttempts)
+	if st.RateLimited {
+		text = i18n.T("llm.rate_limited", secs)
+	}
+	return "\n\n" + ui.StyleHelp.Render(text)
 }
@@ -2,14 +2,21 @@ package states
 
 import (
 	"fmt"
+	"math"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
+	"github.com/erwaen/type-glish/internal/runlog"
 	"github.com/erwaen/type-glish/internal/ui"
 )
 
-type CombatResultState struct{}
+type CombatResultState struct {
+	cfg             *config.Config
+	showExplanation bool
+}
 
 func (s *CombatResultState) Init(ctx *game.Context) tea.Cmd {
 	return nil
@@ -26,12 +33,15 @@ func (s *CombatResultState) Update(msg tea.Msg, ctx *game.Context) (GameState, t
 
 			// Check if enemy is dead
 			if ctx.CurrentEnemy != nil && ctx.CurrentEnemy.HP <= 0 {
-				return &VictoryState{}, nil
+				return &VictoryState{cfg: s.cfg}, nil
 			}
 
 			// Continue combat
 			ctx.CurrentNarrative = ctx.CombatAssessment.Outcome
-			return NewCombatState(), nil
+			return NewCombatState(s.cfg), nil
+		}
+		if msg.String() == "e" {
+			s.showExplanation = !s.showExplanation
 		}
 		if msg.Type == tea.KeyCtrlC {
 			return s, tea.Quit
@@ -46,18 +56,18 @@ func (s *CombatResultState) View(ctx *game.Context) string {
 	var content string
 
 	// Show what the player typed
-	content += ui.StyleSubTitle.Render("YOU SAID:") + "\n"
+	content += ui.StyleSubTitle.Render(i18n.T("result.you_said")) + "\n"
 	content += fmt.Sprintf("> %s\n\n", ctx.LastInput)
 
 	// Show corrected version if different
 	if a.CorrectedSentence != ctx.LastInput {
-		content += ui.StyleSubTitle.Render("CORRECTED:") + "\n"
+		content += ui.StyleSubTitle.Render(i18n.T("result.corrected")) + "\n"
 		content += fmt.Sprintf("> %s\n\n", a.CorrectedSentence)
 	}
 
 	// Outcome
 	content += "───────────────────────────────────────────\n\n"
-	content += ui.StyleSubTitle.Render("RESULT:") + "\n"
+	content += ui.StyleSubTitle.Render(i18n.T("result.result")) + "\n"
 	content += a.Outcome + "\n\n"
 
 	// Score icons based on grammar score
@@ -85,37 +95,157 @@ func (s *CombatResultState) View(ctx *game.Context) string {
 	damageReceivedStyle := lipgloss.NewStyle().Foreground(ui.ColorError).Bold(true)
 
 	// Compact score and damage line
-	content += fmt.Sprintf("Score: %s %s  |  You dealt %s  |  You took %s\n\n",
+	content += i18n.T("result.score_line",
 		scoreStyle.Render(fmt.Sprintf("%d/10", a.GrammarScore)),
 		scoreStyle.Render(scoreIcons),
-		damageDealtStyle.Render(fmt.Sprintf("%d dmg", a.DamageDealt)),
-		damageReceivedStyle.Render(fmt.Sprintf("%d dmg", a.DamageReceived)))
+		damageDealtStyle.Render(i18n.T("common.dmg", a.DamageDealt)),
+		damageReceivedStyle.Render(i18n.T("common.dmg", a.DamageReceived))) + "\n\n"
+
+	// Combo, critical hit, enemy ability and status effects of this turn
+	content += renderStrike(ctx.LastStrike)
+	content += renderTurnEffects(ctx.LastEffects)
 
 	// DM Comment
-	content += ui.StyleSubTitle.Render("DM:") + " " + a.DMComment + "\n\n"
+	content += renderOfflineNotice(a.Offline)
+	injection := i18n.T("result.injection_suspected")
+	if a.Penalty > 0 {
+		injection = i18n.T("result.injection", a.Penalty)
+	}
+	content += renderInjectionWarning(a.InjectionDetected, injection)
+	if a.Repeats > 0 {
+		farmStyle := lipgloss.NewStyle().Foreground(ui.ColorWarning).Bold(true)
+		percent := int(ctx.AntiFarm.Multiplier(a.Repeats) * 100)
+		content += farmStyle.Render(i18n.T("result.farm", a.Repeats, percent)) + "\n"
+	}
+	content += renderDMComment(a.DMComment, ctx.Persona, a.Offline)
+
+	// Native language explanation, toggled with [E]
+	content += renderExplanation(a.Explanation, ctx.ExplanationLanguage, s.showExplanation)
 
 	// Current HP status
 	content += "───────────────────────────────────────────\n\n"
 	if ctx.CurrentEnemy != nil {
 		content += ui.RenderHPBar(ctx.CurrentEnemy.HP, ctx.CurrentEnemy.MaxHP, ctx.CurrentEnemy.Name, 15) + "\n"
 	}
-	content += ui.RenderHPBar(ctx.Stats.HP, 100, "You", 15) + "\n\n"
+	content += ui.RenderHPBar(ctx.Stats.HP, 100, i18n.T("common.you"), 15) + "\n"
+	content += renderStatusEffects(ctx) + "\n"
 
 	// Show error if any (muted grey)
 	if ctx.LastError != "" {
 		errorStyle := lipgloss.NewStyle().Foreground(ui.ColorSubtext).Italic(true)
-		content += errorStyle.Render("(LLM error: "+ctx.LastError+")") + "\n\n"
+		content += errorStyle.Render(i18n.T("result.llm_error", ctx.LastError)) + "\n\n"
+	}
+
+	help := i18n.T("common.continue_help")
+	if a.Explanation != "" {
+		help = i18n.T("result.explanation_help")
+	}
+	content += ui.StyleHelp.Render(help)
+
+	return ui.CenteredView(i18n.T("result.title"), content, true, ctx.Width, ctx.Height)
+}
+
+// renderDMComment renders the DM comment under the persona's name. Offline
+// comments come from the rule-based grader, not the persona.
+func renderDMComment(comment, persona string, offline bool) string {
+	label := i18n.T("common.dm")
+	if !offline {
+		label = i18n.T("common.dm_persona", personaName(persona))
+	}
+	return ui.StyleSubTitle.Render(label) + " " + comment + "\n\n"
+}
+
+// renderStrike tells what the combo and a critical hit added, or that the
+// combo broke
+func renderStrike(st game.Strike) string {
+	var content string
+	if st.Critical {
+		critStyle := lipgloss.NewStyle().Foreground(ui.ColorPrimary).Bold(true)
+		content += critStyle.Render(i18n.T("result.critical")) + "\n"
+	}
+	if st.Combo > 1 {
+		comboStyle := lipgloss.NewStyle().Foreground(ui.ColorSuccess)
+		percent := int(math.Round((st.Multiplier - 1) * 100))
+		content += comboStyle.Render(i18n.T("result.combo", st.Combo, percent, st.Bonus)) + "\n"
+	} else if st.Critical {
+		content += lipgloss.NewStyle().Foreground(ui.ColorSuccess).Render(i18n.T("result.critical_bonus", st.Bonus)) + "\n"
+	}
+	if st.Broken > 0 {
+		content += lipgloss.NewStyle().Foreground(ui.ColorWarning).Render(i18n.T("result.combo_broken", st.Broken)) + "\n"
+	}
+	if content == "" {
+		return ""
 	}
+	return content + "\n"
+}
+
+// renderTurnEffects tells what the enemy's ability and the statuses did
+// during the turn
+func renderTurnEffects(fx game.TurnEffects) string {
+	if !fx.Any() {
+		return ""
+	}
+	abilityStyle := lipgloss.NewStyle().Foreground(ui.ColorError).Bold(true)
+	effectStyle := lipgloss.NewStyle().Foreground(ui.ColorWarning)
 
-	content += ui.StyleHelp.Render("Press [Enter] to continue...")
+	var content string
+	if fx.Ability != "" {
+		content += abilityStyle.Render(i18n.T("result.ability", fx.Ability)) + " " + fx.Narration + "\n"
+		if fx.Bonus > 0 {
+			content += effectStyle.Render(i18n.T("result.ability_bonus", fx.Bonus)) + "\n"
+		}
+	}
+	if fx.Halved > 0 {
+		content += effectStyle.Render(i18n.T("result.stunned", fx.Halved)) + "\n"
+	}
+	if fx.Absorbed > 0 {
+		content += effectStyle.Render(i18n.T("result.shield", fx.Absorbed)) + "\n"
+	}
+	if fx.Poison > 0 {
+		content += effectStyle.Render(i18n.T("result.poison", fx.Poison)) + "\n"
+	}
+	return content + "\n"
+}
 
-	return ui.CenteredView("COMBAT RESULT", content, true, ctx.Width, ctx.Height)
+// renderExplanation renders the native language grammar explanation, or
+// a hint that it can be shown
+func renderExplanation(explanation, language string, show bool) string {
+	if explanation == "" {
+		return ""
+	}
+	if language == "" {
+		language = i18n.T("result.your_language")
+	}
+	if !show {
+		return ui.StyleSubTitle.Render(i18n.T("result.explanation_hint", language)) + "\n\n"
+	}
+	return ui.StyleSubTitle.Render(i18n.T("result.explanation", language)) + "\n" + explanation + "\n\n"
+}
+
+// renderInjectionWarning tells the player the DM caught an attempt to
+// give the grader orders
+func renderInjectionWarning(detected bool, message string) string {
+	if !detected {
+		return ""
+	}
+	style := lipgloss.NewStyle().Foreground(ui.ColorError).Bold(true)
+	return style.Render(message) + "\n"
+}
+
+// renderOfflineNotice labels results graded without an LLM
+func renderOfflineNotice(offline bool) string {
+	if !offline {
+		return ""
+	}
+	style := lipgloss.NewStyle().Foreground(ui.ColorWarning).Italic(true)
+	return style.Render(i18n.T("result.offline")) + "\n"
 }
 
 // GameOverState handles player death
 type GameOverState struct{}
 
 func (s *GameOverState) Init(ctx *game.Context) tea.Cmd {
+	ctx.EndRun(runlog.ResultDefeat)
 	return nil
 }
 
@@ -130,17 +260,7 @@ func (s *GameOverState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.C
 }
 
 func (s *GameOverState) View(ctx *game.Context) string {
-	content := `
-    ╔═══════════════════════════════════════╗
-    ║                                       ║
-    ║            G A M E   O V E R          ║
-    ║                                       ║
-    ║   Your grammar failed you...          ║
-    ║   The Kingdom of Lexicon mourns.      ║
-    ║                                       ║
-    ╚═══════════════════════════════════════╝
-
-    Press [Enter] or [Q] to exit.
-`
-	return ui.CenteredView("💀 DEFEAT 💀", content, true, ctx.Width, ctx.Height)
+	content := ui.RenderBanner(i18n.T("gameover.banner"), i18n.T("gameover.line1"), i18n.T("gameover.line2"))
+	content += "\n    " + i18n.T("gameover.help") + "\n"
+	return ui.CenteredView(i18n.T("gameover.title"), content, true, ctx.Width, ctx.Height)
 }
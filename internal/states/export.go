@@ -0,0 +1,81 @@
+package states
+
+import (
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erwaen/type-glish/internal/config"
+	"github.com/erwaen/type-glish/internal/export"
+	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
+	"github.com/erwaen/type-glish/internal/ui"
+)
+
+// exportDirName is the folder in the config directory the menu exports to
+const exportDirName = "exports"
+
+// ExportState asks before writing the collected corrections and
+// vocabulary to the config directory, then shows where they went
+type ExportState struct {
+	cfg   *config.Config
+	dir   string
+	done  bool
+	paths []string
+	err   error
+}
+
+func NewExportState(cfg *config.Config) *ExportState {
+	return &ExportState{cfg: cfg}
+}
+
+func (s *ExportState) Init(ctx *game.Context) tea.Cmd {
+	s.dir, s.err = config.GetDataPath(exportDirName)
+	if abs, err := filepath.Abs(s.dir); err == nil {
+		s.dir = abs
+	}
+	return nil
+}
+
+func (s *ExportState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return s, tea.Quit
+		case "enter":
+			if s.done || s.err != nil {
+				return NewMenuState(s.cfg), nil
+			}
+			s.paths, s.err = export.Write(s.dir, export.FormatAll, ctx.Deck.Cards, ctx.Vocab.Entries)
+			s.done = true
+		case "esc", "q":
+			return NewMenuState(s.cfg), nil
+		}
+	}
+	return s, nil
+}
+
+func (s *ExportState) View(ctx *game.Context) string {
+	var content string
+
+	if !s.done && s.err == nil {
+		content += i18n.T("export.confirm", len(ctx.Deck.Cards), len(ctx.Vocab.Entries)) + "\n\n"
+		content += "  " + s.dir + "\n\n"
+		content += ui.StyleHelp.Render(i18n.T("export.help"))
+		return ui.CenteredView(i18n.T("export.title"), content, true, ctx.Width, ctx.Height)
+	}
+
+	if s.err != nil {
+		content += ui.StyleDamageReceived.Render(i18n.T("export.failed", s.err)) + "\n\n"
+	} else {
+		content += ui.StyleSubTitle.Render(i18n.T("export.ready")) + "\n\n"
+		for _, p := range s.paths {
+			content += "  " + p + "\n"
+		}
+		content += "\n" + i18n.T("export.anki_hint") + "\n"
+	}
+
+	content += ui.StyleHelp.Render(i18n.T("common.back_help"))
+
+	return ui.CenteredView(i18n.T("export.title"), content, true, ctx.Width, ctx.Height)
+}
@@ -0,0 +1,216 @@
+package states
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erwaen/type-glish/internal/config"
+	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
+	"github.com/erwaen/type-glish/internal/runlog"
+	"github.com/erwaen/type-glish/internal/ui"
+)
+
+// HistoryKey opens the history of the current run during play
+const HistoryKey = "ctrl+l"
+
+// OpenHistory returns a history screen for the current run on top of the
+// given state, if that state can be safely resumed afterwards
+func OpenHistory(current GameState, ctx *game.Context) (GameState, bool) {
+	if ctx.Run == nil {
+		return nil, false
+	}
+	switch current.(type) {
+	case *CombatState, *CombatResultState, *PathChoiceState, *PathResultState:
+		return NewHistoryState(ctx.Run, current), true
+	}
+	return nil, false
+}
+
+// HistoryState shows the turns of a run in a scrollable viewport
+type HistoryState struct {
+	run      *runlog.Run
+	back     GameState // State to return to on Esc
+	viewport viewport.Model
+}
+
+func NewHistoryState(run *runlog.Run, back GameState) *HistoryState {
+	return &HistoryState{run: run, back: back}
+}
+
+func (s *HistoryState) Init(ctx *game.Context) tea.Cmd {
+	s.viewport = viewport.New(historyWidth(), historyHeight(ctx))
+	s.viewport.SetContent(renderRun(s.run))
+	// Jump to the latest turn when looking at the run in progress
+	if s.run.Ended.IsZero() {
+		s.viewport.GotoBottom()
+	}
+	return nil
+}
+
+func (s *HistoryState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.viewport.Width = historyWidth()
+		s.viewport.Height = historyHeight(ctx)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return s, tea.Quit
+		case "esc", "q", "enter", HistoryKey:
+			return s.back, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	s.viewport, cmd = s.viewport.Update(msg)
+	return s, cmd
+}
+
+func (s *HistoryState) View(ctx *game.Context) string {
+	var content string
+
+	content += ui.StyleSubTitle.Render(runSummary(s.run)) + "\n\n"
+	content += s.viewport.View() + "\n"
+	content += ui.StyleHelp.Render(i18n.T("history.help", int(s.viewport.ScrollPercent()*100)))
+
+	return ui.CenteredView(i18n.T("history.title"), content, true, ctx.Width, ctx.Height)
+}
+
+// historyWidth fits the viewport inside the box padding
+func historyWidth() int {
+	return ui.WidthMain - 8
+}
+
+// historyHeight leaves room for the box, title and help lines
+func historyHeight(ctx *game.Context) int {
+	return max(8, ctx.Height-14)
+}
+
+// runSummary returns a one-line description of a run
+func runSummary(run *runlog.Run) string {
+	result := i18n.T("history.unfinished")
+	if run.Result != "" {
+		result = i18n.T("history.result." + run.Result)
+	}
+	summary := i18n.T("history.summary",
+		run.Started.Format("2006-01-02 15:04"), run.Difficulty, len(run.Turns), run.EnemiesDefeated, result)
+	if run.BestCombo > 1 || run.Criticals > 0 {
+		summary += "  " + i18n.T("history.combo_summary", run.BestCombo, run.Criticals)
+	}
+	return summary
+}
+
+// renderRun renders every turn of a run
+func renderRun(run *runlog.Run) string {
+	if len(run.Turns) == 0 {
+		return i18n.T("history.empty")
+	}
+
+	var sb strings.Builder
+	for i, t := range run.Turns {
+		header := fmt.Sprintf("#%d %s  %s", i+1, t.Time.Format("15:04:05"), i18n.T("history.kind."+t.Kind))
+		if t.Enemy != "" {
+			header += "  " + ui.StyleEnemyName.Render(t.Enemy)
+		}
+		sb.WriteString(ui.StyleSubTitle.Render(header) + "\n")
+		sb.WriteString("> " + t.Input + "\n")
+		if t.Corrected != "" && t.Corrected != t.Input {
+			sb.WriteString(ui.StyleDamageDealt.Render("✓ ") + t.Corrected + "\n")
+		}
+
+		stats := i18n.T("history.score", t.Score)
+		if t.DamageDealt > 0 {
+			stats += "  " + ui.StyleDamageDealt.Render(i18n.T("history.dealt", t.DamageDealt))
+		}
+		if t.DamageReceived > 0 {
+			stats += "  " + ui.StyleDamageReceived.Render(i18n.T("history.took", t.DamageReceived))
+		}
+		if t.Healing > 0 {
+			stats += "  " + ui.StyleDamageDealt.Render(i18n.T("history.healed", t.Healing))
+		}
+		if t.Combo > 1 {
+			stats += "  " + i18n.T("history.combo", t.Combo)
+		}
+		if t.Critical {
+			stats += "  " + ui.StyleDamageDealt.Render(i18n.T("history.critical"))
+		}
+		sb.WriteString(stats + "\n")
+
+		if t.DMComment != "" {
+			sb.WriteString(i18n.T("common.dm") + " " + t.DMComment + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// RunListState lists past runs so their history can be opened from the menu
+type RunListState struct {
+	cfg    *config.Config
+	runs   []*runlog.Run
+	cursor int
+	err    error
+}
+
+func NewRunListState(cfg *config.Config) *RunListState {
+	return &RunListState{cfg: cfg}
+}
+
+func (s *RunListState) Init(ctx *game.Context) tea.Cmd {
+	s.runs, s.err = runlog.LoadRuns()
+	return nil
+}
+
+func (s *RunListState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return s, tea.Quit
+		case "esc", "q":
+			return NewMenuState(s.cfg), nil
+		case "up", "k":
+			if s.cursor > 0 {
+				s.cursor--
+			}
+		case "down", "j":
+			if s.cursor < len(s.runs)-1 {
+				s.cursor++
+			}
+		case "enter":
+			if len(s.runs) == 0 {
+				return NewMenuState(s.cfg), nil
+			}
+			return NewHistoryState(s.runs[s.cursor], s), nil
+		}
+	}
+	return s, nil
+}
+
+// maxListedRuns keeps the run list inside the box
+const maxListedRuns = 12
+
+func (s *RunListState) View(ctx *game.Context) string {
+	var content string
+
+	switch {
+	case s.err != nil:
+		content += ui.StyleDamageReceived.Render(i18n.T("history.load_failed", s.err)) + "\n\n"
+	case len(s.runs) == 0:
+		content += i18n.T("history.no_runs") + "\n\n"
+	default:
+		// Scroll the list window with the cursor
+		start := max(0, s.cursor-maxListedRuns+1)
+		end := min(len(s.runs), start+maxListedRuns)
+		for i := start; i < end; i++ {
+			content += ui.RenderMenuItem(runSummary(s.runs[i]), s.cursor == i) + "\n"
+		}
+	}
+
+	content += ui.StyleHelp.Render("\n" + i18n.T("history.list_help"))
+
+	return ui.CenteredView(i18n.T("history.list_title"), content, true, ctx.Width, ctx.Height)
+}
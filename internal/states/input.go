@@ -3,17 +3,31 @@ package states
 import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
 	"github.com/erwaen/type-glish/internal/ui"
 )
 
+// Free Adventure mode loops NarrativeState -> InputState ->
+// ProcessingState -> ResultState -> NarrativeState, with open-ended
+// actions graded by the critic prompt and scored in the adventure journal.
+
+// NewFreeAdventure starts Free Adventure mode where the journal's story
+// left off
+func NewFreeAdventure(ctx *game.Context, cfg *config.Config) GameState {
+	ctx.CurrentNarrative = ctx.Journal.Scene()
+	return &NarrativeState{Content: ctx.CurrentNarrative, cfg: cfg}
+}
+
 type InputState struct {
 	textInput textinput.Model
+	cfg       *config.Config
 }
 
 func (s *InputState) Init(ctx *game.Context) tea.Cmd {
 	ti := textinput.New()
-	ti.Placeholder = "Write here what you are gonna do?"
+	ti.Placeholder = i18n.T("input.placeholder")
 	ti.Focus()
 	ti.CharLimit = 156
 	ti.Width = 50
@@ -29,9 +43,14 @@ func (s *InputState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd)
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyEnter:
+			if s.textInput.Value() == "" {
+				return s, nil
+			}
 			ctx.LastInput = s.textInput.Value()
-			return &ProcessingState{}, nil
-		case tea.KeyCtrlC, tea.KeyEsc:
+			return &ProcessingState{cfg: s.cfg}, nil
+		case tea.KeyEsc:
+			return NewMenuState(s.cfg), nil
+		case tea.KeyCtrlC:
 			return s, tea.Quit
 		}
 	}
@@ -46,7 +65,15 @@ func (s *InputState) View(ctx *game.Context) string {
 		narrative = ctx.CurrentNarrative + "\n\n---\n\n"
 	}
 
-	content := narrative + "Describe your action in English:\n\n" + s.textInput.View()
+	content := renderAdventureStatus(ctx) + "\n\n" + narrative + i18n.T("input.describe") + "\n\n" + s.textInput.View()
+	content += "\n\n" + ui.StyleHelp.Render(i18n.T("input.help"))
+
+	return ui.CenteredView(i18n.T("input.title"), content, true, ctx.Width, ctx.Height)
+}
 
-	return ui.CenteredView("YOUR ACTION", content, true, ctx.Width, ctx.Height)
+// renderAdventureStatus renders the Free Adventure score line
+func renderAdventureStatus(ctx *game.Context) string {
+	j := ctx.Journal
+	return ui.StyleSubTitle.Render(i18n.T("adventure.status",
+		j.TotalPoints, j.Streak, j.BestStreak, j.Actions))
 }
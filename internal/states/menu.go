@@ -1,15 +1,28 @@
 package states
 
 import (
-	"fmt"
+	"log"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
 	"github.com/erwaen/type-glish/internal/ui"
 )
 
-// MenuState is the Main Menu (Start Game, Settings)
+// Main menu entries, also used as message catalog keys
+const (
+	menuStart    = "menu.start"
+	menuFree     = "menu.adventure"
+	menuReview   = "menu.review"
+	menuStats    = "menu.stats"
+	menuHistory  = "menu.history"
+	menuExport   = "menu.export"
+	menuSettings = "menu.settings"
+)
+
+// MenuState is the Main Menu (Start Game, Review, Stats, Settings)
 type MenuState struct {
 	choices []string
 	cursor  int
@@ -18,7 +31,7 @@ type MenuState struct {
 
 func NewMenuState(cfg *config.Config) *MenuState {
 	return &MenuState{
-		choices: []string{"Start Game", "Settings"},
+		choices: []string{menuStart, menuFree, menuReview, menuStats, menuHistory, menuExport, menuSettings},
 		cursor:  0,
 		cfg:     cfg,
 	}
@@ -43,8 +56,8 @@ func (s *MenuState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd)
 				s.cursor++
 			}
 		case "enter":
-			if s.cursor == 0 {
-				// Start Game
+			switch s.choices[s.cursor] {
+			case menuStart:
 				if s.cfg != nil && s.cfg.Provider == "" {
 					return NewSettingsState(s.cfg), nil
 				}
@@ -52,22 +65,38 @@ func (s *MenuState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd)
 					return NewAPIInputState(s.cfg), nil
 				}
 
-				// Reset player stats for new game
-				ctx.Stats.HP = 100
-				ctx.Stats.XP = 0
-				ctx.Stats.Gold = 0
+				// Reset player stats and start a new run log
+				ctx.StartRun()
 
 				// Spawn first enemy
-				ctx.CurrentEnemy = game.RandomEnemy()
-				ctx.CurrentNarrative = fmt.Sprintf(
-					"You enter the Kingdom of Lexicon, where words have power. A %s blocks your path! %s",
-					ctx.CurrentEnemy.Name,
-					ctx.CurrentEnemy.Description,
-				)
-
-				return NewCombatState(), nil
-			} else if s.cursor == 1 {
-				// Settings
+				enemy := ctx.SpawnEnemy()
+				arrival := game.Scene{Kind: game.SceneArrival, Enemy: enemy}
+
+				// Top up the bestiary in the background for later fights
+				var grow tea.Cmd
+				if ctx.GenerateEnemies {
+					grow = growBestiary(ctx)
+				}
+
+				var next GameState = NewCombatState(s.cfg)
+				if s.cfg != nil && s.cfg.TypingWarmup {
+					next = NewWarmupState(s.cfg)
+				}
+				return sceneTransition(ctx, arrival, next), grow
+			case menuFree:
+				if s.cfg != nil && s.cfg.Provider == "" {
+					return NewSettingsState(s.cfg), nil
+				}
+				return NewFreeAdventure(ctx, s.cfg), nil
+			case menuReview:
+				return NewReviewState(s.cfg, ctx.Deck.Due(time.Now())), nil
+			case menuStats:
+				return NewStatsState(s.cfg), nil
+			case menuHistory:
+				return NewRunListState(s.cfg), nil
+			case menuExport:
+				return NewExportState(s.cfg), nil
+			case menuSettings:
 				return NewSettingsState(s.cfg), nil
 			}
 		}
@@ -78,15 +107,33 @@ func (s *MenuState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd)
 func (s *MenuState) View(ctx *game.Context) string {
 	var content string
 
-	content += ui.StyleSubTitle.Render("Welcome to Type-Glish") + "\n\n"
-	content += "A grammar-powered dungeon crawler where\n"
-	content += "your English skills are your weapon!\n\n"
+	content += ui.StyleSubTitle.Render(i18n.T("menu.welcome")) + "\n\n"
+	content += i18n.T("menu.tagline") + "\n\n"
 
 	for i, choice := range s.choices {
-		content += ui.RenderMenuItem(choice, s.cursor == i) + "\n"
+		content += ui.RenderMenuItem(i18n.T(choice), s.cursor == i) + "\n"
 	}
 
-	content += ui.StyleHelp.Render("\n(Use ↑/↓ to move, Enter to select, q to quit)")
+	content += ui.StyleHelp.Render("\n" + i18n.T("menu.help"))
+
+	return ui.CenteredView(i18n.T("menu.title"), content, true, ctx.Width, ctx.Height)
+}
 
-	return ui.CenteredView("⚔ TYPE-GLISH ⚔", content, true, ctx.Width, ctx.Height)
+// bestiaryGrownMsg reports a background bestiary generation
+type bestiaryGrownMsg struct {
+	added int
+	err   error
+}
+
+// growBestiary asks the LLM for new enemies without blocking the game
+func growBestiary(ctx *game.Context) tea.Cmd {
+	return func() tea.Msg {
+		added, err := ctx.GrowBestiary()
+		if err != nil {
+			log.Printf("Error generating enemies: %v", err)
+		} else if added > 0 {
+			log.Printf("Added %d generated enemies to the bestiary", added)
+		}
+		return bestiaryGrownMsg{added: added, err: err}
+	}
 }
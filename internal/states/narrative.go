@@ -1,32 +1,132 @@
 package states
 
 import (
+	"log"
+
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
+	"github.com/erwaen/type-glish/internal/llm"
 	"github.com/erwaen/type-glish/internal/ui"
 )
 
+// NarrativeState shows DM narration. Scene transitions ask the LLM for it
+// and fall back to the scene's static text when it fails; Enter skips the
+// wait and keeps the static text.
 type NarrativeState struct {
-	Content string // The story text
+	Content string    // The story text
+	Next    GameState // State after Enter, the Free Adventure input if nil
+
+	cfg     *config.Config // Free Adventure only, for the way back to the menu
+	scene   *game.Scene
+	id      int
+	loading bool
+	spinner spinner.Model
 }
 
-func (s NarrativeState) Init(ctx *game.Context) tea.Cmd {
-	return nil
+// narrationMsg tags a narration with the scene it was asked for, so a
+// late reply can't land on a later scene
+type narrationMsg struct {
+	id int
+	llm.NarrationMsg
 }
 
-func (s NarrativeState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd) {
+// sceneCount numbers the narrated scenes
+var sceneCount int
+
+// sceneTransition moves to next through a narrated scene, or straight to
+// next with the static text when narration is off
+func sceneTransition(ctx *game.Context, scene game.Scene, next GameState) GameState {
+	if !ctx.Narration {
+		if scene.Kind != game.SceneCrossroads {
+			ctx.CurrentNarrative = scene.StaticText()
+		}
+		return next
+	}
+
+	sceneCount++
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(ui.ColorPrimary)
+	return &NarrativeState{
+		Content: scene.StaticText(),
+		Next:    next,
+		scene:   &scene,
+		id:      sceneCount,
+		loading: true,
+		spinner: s,
+	}
+}
+
+func (s *NarrativeState) Init(ctx *game.Context) tea.Cmd {
+	if !s.loading {
+		return nil
+	}
+	req := ctx.NarrationRequest(*s.scene)
+	id := s.id
+	return tea.Batch(
+		s.spinner.Tick,
+		func() tea.Msg {
+			return narrationMsg{id: id, NarrationMsg: ctx.LLMClient.Narrate(req).(llm.NarrationMsg)}
+		},
+	)
+}
+
+func (s *NarrativeState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd) {
 	switch msg := msg.(type) {
+	case narrationMsg:
+		if msg.id != s.id || !s.loading {
+			return s, nil
+		}
+		s.loading = false
+		if msg.Err != nil {
+			log.Printf("Error narrating scene: %v", msg.Err)
+		} else {
+			s.Content = msg.Text
+		}
+
+	case spinner.TickMsg:
+		if s.loading {
+			var cmd tea.Cmd
+			s.spinner, cmd = s.spinner.Update(msg)
+			return s, cmd
+		}
+
 	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return s, tea.Quit
+		}
+		if s.scene == nil && msg.String() == "esc" {
+			return NewMenuState(s.cfg), nil
+		}
 		if msg.String() == "enter" {
-			// Transition to Input Mode
-			return &InputState{}, nil
+			if s.scene == nil {
+				// Transition to Input Mode
+				return &InputState{cfg: s.cfg}, nil
+			}
+			s.loading = false
+			if s.scene.Kind != game.SceneCrossroads {
+				ctx.CurrentNarrative = s.Content
+			}
+			return s.Next, nil
 		}
 	}
 	return s, nil
 }
 
-func (s NarrativeState) View(ctx *game.Context) string {
-	hint := ui.StyleHelp.Render("Press [Enter] to take action... (Ctrl+S for Settings)")
+func (s *NarrativeState) View(ctx *game.Context) string {
+	if s.loading {
+		content := s.spinner.View() + " " + i18n.T("narrative.loading") + "\n\n"
+		content += ui.StyleHelp.Render(i18n.T("narrative.skip_help"))
+		return ui.CenteredView(i18n.T("narrative.title"), content, true, ctx.Width, ctx.Height)
+	}
 
-	return ui.CenteredView("DUNGEON MASTER", s.Content+"\n\n"+hint, true, ctx.Width, ctx.Height)
+	hint := ui.StyleHelp.Render(i18n.T("narrative.help"))
+	if s.scene != nil {
+		hint = ui.StyleHelp.Render(i18n.T("common.continue_help"))
+	}
+	return ui.CenteredView(i18n.T("narrative.title"), s.Content+"\n\n"+hint, true, ctx.Width, ctx.Height)
 }
@@ -4,13 +4,18 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
 	"github.com/erwaen/type-glish/internal/llm"
+	"github.com/erwaen/type-glish/internal/offline"
+	"github.com/erwaen/type-glish/internal/runlog"
 	"github.com/erwaen/type-glish/internal/ui"
 )
 
@@ -27,6 +32,7 @@ var PathOptions = []struct {
 }
 
 type PathChoiceState struct {
+	cfg       *config.Config
 	textInput textinput.Model
 	paths     []struct {
 		Name        string
@@ -34,9 +40,9 @@ type PathChoiceState struct {
 	}
 }
 
-func NewPathChoiceState() *PathChoiceState {
+func NewPathChoiceState(cfg *config.Config) *PathChoiceState {
 	ti := textinput.New()
-	ti.Placeholder = "Describe which path you take..."
+	ti.Placeholder = i18n.T("path.placeholder")
 	ti.Focus()
 	ti.CharLimit = 200
 	ti.Width = 50
@@ -52,11 +58,21 @@ func NewPathChoiceState() *PathChoiceState {
 	})
 
 	return &PathChoiceState{
+		cfg:       cfg,
 		textInput: ti,
 		paths:     shuffled[:3],
 	}
 }
 
+// pathDescriptions lists the offered paths for the crossroads narration
+func (s *PathChoiceState) pathDescriptions() []string {
+	var paths []string
+	for i, p := range s.paths {
+		paths = append(paths, fmt.Sprintf("%d. %s - %s", i+1, p.Name, p.Description))
+	}
+	return paths
+}
+
 func (s *PathChoiceState) Init(ctx *game.Context) tea.Cmd {
 	return textinput.Blink
 }
@@ -71,11 +87,8 @@ func (s *PathChoiceState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea
 			if s.textInput.Value() != "" {
 				ctx.LastInput = s.textInput.Value()
 				// Build path options string for LLM
-				pathStr := ""
-				for i, p := range s.paths {
-					pathStr += fmt.Sprintf("%d. %s - %s\n", i+1, p.Name, p.Description)
-				}
-				return &PathProcessingState{pathOptions: pathStr}, nil
+				pathStr := strings.Join(s.pathDescriptions(), "\n") + "\n"
+				return &PathProcessingState{cfg: s.cfg, pathOptions: pathStr}, nil
 			}
 		case tea.KeyCtrlC:
 			return s, tea.Quit
@@ -92,9 +105,9 @@ func (s *PathChoiceState) View(ctx *game.Context) string {
 	// Status bar at top
 	content += ui.RenderStatusBar(ctx.Stats.HP, 100, ctx.Stats.Gold, ctx.Stats.XP) + "\n\n"
 
-	content += ui.StyleSubTitle.Render("You come to a crossroads...") + "\n\n"
+	content += ui.StyleSubTitle.Render(i18n.T("path.crossroads")) + "\n\n"
 
-	content += "Choose your path:\n\n"
+	content += i18n.T("path.choose") + "\n\n"
 	for i, p := range s.paths {
 		content += fmt.Sprintf("  %d. %s\n", i+1, ui.StyleEnemyName.Render(p.Name))
 		content += fmt.Sprintf("     %s\n\n", ui.StyleSubTitle.Render(p.Description))
@@ -102,16 +115,17 @@ func (s *PathChoiceState) View(ctx *game.Context) string {
 
 	content += "───────────────────────────────────────────\n\n"
 
-	content += "Describe your choice in a complete sentence:\n"
+	content += i18n.T("path.describe") + "\n"
 	content += "> " + s.textInput.View() + "\n\n"
 
-	content += ui.StyleHelp.Render("(Better grammar = more healing!)")
+	content += ui.StyleHelp.Render(i18n.T("path.help"))
 
-	return ui.CenteredView("CROSSROADS", content, true, ctx.Width, ctx.Height)
+	return ui.CenteredView(i18n.T("path.title"), content, true, ctx.Width, ctx.Height)
 }
 
 // PathProcessingState processes the path choice
 type PathProcessingState struct {
+	cfg         *config.Config
 	spinner     spinner.Model
 	pathOptions string
 }
@@ -121,12 +135,19 @@ func (s *PathProcessingState) Init(ctx *game.Context) tea.Cmd {
 	s.spinner.Spinner = spinner.Dot
 	s.spinner.Style = lipgloss.NewStyle().Foreground(ui.ColorPrimary)
 
-	pathOpts := s.pathOptions
+	req := llm.PathRequest{
+		Choice:  ctx.LastInput,
+		Options: s.pathOptions,
+		Level:   ctx.Level(),
+		Persona: ctx.Persona,
+
+		ExplanationLanguage: ctx.ExplanationLanguage,
+	}
 
 	return tea.Batch(
 		s.spinner.Tick,
 		func() tea.Msg {
-			return ctx.LLMClient.AnalyzePathChoice(ctx.LastInput, pathOpts)
+			return ctx.LLMClient.AnalyzePathChoice(req)
 		},
 	)
 }
@@ -134,40 +155,45 @@ func (s *PathProcessingState) Init(ctx *game.Context) tea.Cmd {
 func (s *PathProcessingState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd) {
 	switch msg := msg.(type) {
 	case llm.PathAssessmentMsg:
+		a := msg.Data
 		if msg.Err != nil {
 			log.Printf("Error from LLM: %v", msg.Err)
-			// Default healing on error
-			healing := 10
-			ctx.Stats.HP += healing
-			if ctx.Stats.HP > 100 {
-				ctx.Stats.HP = 100
-			}
-			return &PathResultState{
-				healing:   healing,
-				outcome:   "You find a peaceful spot to rest...",
-				dmComment: "The narrator lost their notes.",
-				corrected: ctx.LastInput,
-				score:     5,
-			}, nil
+			// Without a provider, grade with the offline rules instead
+			a = offline.GradePath(ctx.LastInput)
+		}
+		game.ValidatePathAssessment(&a)
+
+		result := &PathResultState{
+			cfg:       s.cfg,
+			healing:   a.Healing,
+			outcome:   a.Outcome,
+			dmComment: a.DMComment,
+			corrected: a.CorrectedSentence,
+			score:     a.GrammarScore,
+			injection: a.InjectionDetected,
+			offline:   a.Offline,
+
+			explanation: a.Explanation,
 		}
 
 		// Apply healing
-		healing := msg.Data.Healing
-		if healing > 20 {
-			healing = 20
-		}
-		ctx.Stats.HP += healing
+		ctx.Stats.HP += result.healing
 		if ctx.Stats.HP > 100 {
 			ctx.Stats.HP = 100
 		}
 
-		return &PathResultState{
-			healing:   healing,
-			outcome:   msg.Data.Outcome,
-			dmComment: msg.Data.DMComment,
-			corrected: msg.Data.CorrectedSentence,
-			score:     msg.Data.GrammarScore,
-		}, nil
+		ctx.RecordTurn(runlog.Turn{
+			Kind:      runlog.KindPath,
+			Input:     ctx.LastInput,
+			Corrected: result.corrected,
+			Score:     result.score,
+			Healing:   result.healing,
+			DMComment: result.dmComment,
+			Outcome:   result.outcome,
+			Prompt:    a.Prompt,
+		})
+
+		return result, nil
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -185,17 +211,24 @@ func (s *PathProcessingState) Update(msg tea.Msg, ctx *game.Context) (GameState,
 
 func (s *PathProcessingState) View(ctx *game.Context) string {
 	spin := s.spinner.View()
-	content := fmt.Sprintf("%s The Dungeon Master considers your path...", spin)
-	return ui.CenteredView("🛤 CROSSROADS 🛤", content, true, ctx.Width, ctx.Height)
+	content := fmt.Sprintf("%s %s", spin, i18n.T("path.processing"))
+	content += renderRetryStatus(ctx)
+	return ui.CenteredView(i18n.T("path.processing_title"), content, true, ctx.Width, ctx.Height)
 }
 
 // PathResultState shows the result of path choice
 type PathResultState struct {
+	cfg       *config.Config
 	healing   int
 	outcome   string
 	dmComment string
 	corrected string
 	score     int
+	injection bool
+	offline   bool
+
+	explanation     string
+	showExplanation bool
 }
 
 func (s *PathResultState) Init(ctx *game.Context) tea.Cmd {
@@ -207,10 +240,12 @@ func (s *PathResultState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea
 	case tea.KeyMsg:
 		if msg.String() == "enter" {
 			// Spawn new enemy and go to combat
-			ctx.CurrentEnemy = game.RandomEnemy()
-			ctx.CurrentNarrative = fmt.Sprintf("As you travel, a %s blocks your path! %s",
-				ctx.CurrentEnemy.Name, ctx.CurrentEnemy.Description)
-			return NewCombatState(), nil
+			enemy := ctx.SpawnEnemy()
+			scene := game.Scene{Kind: game.SceneEncounter, Enemy: enemy, Travelled: true}
+			return sceneTransition(ctx, scene, NewCombatState(s.cfg)), nil
+		}
+		if msg.String() == "e" {
+			s.showExplanation = !s.showExplanation
 		}
 		if msg.Type == tea.KeyCtrlC {
 			return s, tea.Quit
@@ -222,7 +257,7 @@ func (s *PathResultState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea
 func (s *PathResultState) View(ctx *game.Context) string {
 	var content string
 
-	content += ui.StyleSubTitle.Render("YOUR CHOICE:") + "\n"
+	content += ui.StyleSubTitle.Render(i18n.T("path.your_choice")) + "\n"
 	content += "> " + s.corrected + "\n\n"
 
 	content += "───────────────────────────────────────────\n\n"
@@ -251,17 +286,25 @@ func (s *PathResultState) View(ctx *game.Context) string {
 	scoreStyle := lipgloss.NewStyle().Foreground(scoreColor).Bold(true)
 	healStyle := lipgloss.NewStyle().Foreground(ui.ColorSuccess).Bold(true)
 
-	content += fmt.Sprintf("Score: %s %s  |  Health Restored: %s\n\n",
+	content += i18n.T("path.score_line",
 		scoreStyle.Render(fmt.Sprintf("%d/10", s.score)),
 		scoreStyle.Render(scoreIcons),
-		healStyle.Render(fmt.Sprintf("+%d", s.healing)))
+		healStyle.Render(fmt.Sprintf("+%d", s.healing))) + "\n\n"
+
+	content += renderOfflineNotice(s.offline)
+	content += renderInjectionWarning(s.injection, i18n.T("path.injection"))
+	content += renderDMComment(s.dmComment, ctx.Persona, s.offline)
 
-	content += ui.StyleSubTitle.Render("DM:") + " " + s.dmComment + "\n\n"
+	content += renderExplanation(s.explanation, ctx.ExplanationLanguage, s.showExplanation)
 
 	content += "───────────────────────────────────────────\n\n"
 	content += ui.RenderStatusBar(ctx.Stats.HP, 100, ctx.Stats.Gold, ctx.Stats.XP) + "\n\n"
 
-	content += ui.StyleHelp.Render("Press [Enter] to continue...")
+	help := i18n.T("common.continue_help")
+	if s.explanation != "" {
+		help = i18n.T("result.explanation_help")
+	}
+	content += ui.StyleHelp.Render(help)
 
-	return ui.CenteredView("PATH RESULT", content, true, ctx.Width, ctx.Height)
+	return ui.CenteredView(i18n.T("path.result_title"), content, true, ctx.Width, ctx.Height)
 }
@@ -6,8 +6,11 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
 	"github.com/erwaen/type-glish/internal/llm"
+	"github.com/erwaen/type-glish/internal/offline"
 
 	spinner "github.com/charmbracelet/bubbles/spinner"
 	"github.com/erwaen/type-glish/internal/ui"
@@ -15,6 +18,7 @@ import (
 
 type ProcessingState struct {
 	spinner spinner.Model
+	cfg     *config.Config
 }
 
 func (s *ProcessingState) Init(ctx *game.Context) tea.Cmd {
@@ -22,11 +26,13 @@ func (s *ProcessingState) Init(ctx *game.Context) tea.Cmd {
 	s.spinner.Spinner = spinner.Dot
 	s.spinner.Style = lipgloss.NewStyle().Foreground(ui.ColorPrimary)
 
+	req := ctx.ActionRequest(ctx.LastInput)
+
 	// Fire off the LLM analysis command
 	return tea.Batch(
 		s.spinner.Tick,
 		func() tea.Msg {
-			return ctx.LLMClient.AnalyzeAction(ctx.LastInput)
+			return ctx.LLMClient.AnalyzeAction(req)
 		},
 	)
 }
@@ -35,19 +41,17 @@ func (s *ProcessingState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea
 	switch msg := msg.(type) {
 	// Handle the API Response
 	case llm.AssessmentMsg:
+		a := msg.Data
 		if msg.Err != nil {
 			log.Printf("Error from LLM: %v", msg.Err)
-			// Return to input state or handle error gracefully?
-			// For now, let's just log and maybe set a dummy error message in context so user knows?
-			// But the user just asked "how can I see the errors".
-			// Let's pass the error to the UI logic if possible, or just log.
-			// Going to ResultState with empty data is confusing.
-			// Let's go back to InputState but maybe we need a way to show error.
+			// Without a provider, grade with the offline rules instead
+			a = offline.GradeAction(ctx.LastInput)
 		}
-		ctx.LastAssessment = msg.Data // Save result to context
+		entry := ctx.RecordAdventure(&a)
+		ctx.LastAssessment = a // Save result to context
 
 		// Transition to Result Screen
-		return &ResultState{}, nil
+		return &ResultState{cfg: s.cfg, entry: entry}, nil
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -63,8 +67,9 @@ func (s *ProcessingState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea
 	return s, nil
 }
 
-func (s ProcessingState) View(ctx *game.Context) string {
+func (s *ProcessingState) View(ctx *game.Context) string {
 	spin := s.spinner.View()
-	content := fmt.Sprintf("%s The Dungeon Master is judging your grammar...", spin)
-	return ui.CenteredView("THINKING...", content, true, ctx.Width, ctx.Height)
+	content := fmt.Sprintf("%s %s", spin, i18n.T("processing.judging"))
+	content += renderRetryStatus(ctx)
+	return ui.CenteredView(i18n.T("processing.title"), content, true, ctx.Width, ctx.Height)
 }
@@ -5,11 +5,18 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/erwaen/type-glish/internal/adventure"
+	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
 	"github.com/erwaen/type-glish/internal/ui"
 )
 
-type ResultState struct{}
+// ResultState shows the grade and points of a Free Adventure action
+type ResultState struct {
+	cfg   *config.Config
+	entry adventure.Entry
+}
 
 func (s *ResultState) Init(ctx *game.Context) tea.Cmd {
 	return nil
@@ -18,16 +25,15 @@ func (s *ResultState) Init(ctx *game.Context) tea.Cmd {
 func (s *ResultState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if msg.String() == "enter" {
-			// Update Context Stats or History here if needed
-			// Update current narrative for next turn
+		switch msg.String() {
+		case "enter":
+			// The outcome becomes the scene of the next action
 			ctx.CurrentNarrative = ctx.LastAssessment.OutcomeDescription
-
-			// For now, loop back to Narrative with the outcome
-			nextNarrative := &NarrativeState{
-				Content: ctx.CurrentNarrative,
-			}
-			return nextNarrative, nil
+			return &NarrativeState{Content: ctx.CurrentNarrative, cfg: s.cfg}, nil
+		case "esc":
+			return NewMenuState(s.cfg), nil
+		case "ctrl+c":
+			return s, tea.Quit
 		}
 	}
 	return s, nil
@@ -37,19 +43,41 @@ func (s *ResultState) View(ctx *game.Context) string {
 	a := ctx.LastAssessment
 
 	scoreColor := ui.ColorError
-	if a.GrammarScore > 7 {
+	if a.GrammarScore >= adventure.StreakScore {
 		scoreColor = ui.ColorSuccess
+	} else if a.GrammarScore >= 5 {
+		scoreColor = ui.ColorWarning
 	}
 
 	scoreStyle := lipgloss.NewStyle().Foreground(scoreColor).Bold(true)
-	scoreText := scoreStyle.Render(fmt.Sprintf("%d/10", a.GrammarScore))
+	pointsStyle := lipgloss.NewStyle().Foreground(ui.ColorSuccess).Bold(true)
+
+	content := ""
+	if a.CorrectedSentence != "" && a.CorrectedSentence != ctx.LastInput {
+		content += ui.StyleSubTitle.Render(i18n.T("result.corrected")) + "\n"
+		content += fmt.Sprintf("> %s\n\n", a.CorrectedSentence)
+	}
+
+	content += a.OutcomeDescription + "\n\n"
+	content += i18n.T("adventure.score_line",
+		scoreStyle.Render(fmt.Sprintf("%d/10", a.GrammarScore)),
+		pointsStyle.Render(fmt.Sprintf("+%d", s.entry.Points))) + "\n"
+	if s.entry.Streak > 1 {
+		content += pointsStyle.Render(i18n.T("adventure.streak", s.entry.Streak)) + "\n"
+	}
+	content += "\n"
+
+	content += renderOfflineNotice(a.Offline)
+	content += renderInjectionWarning(a.InjectionDetected, i18n.T("adventure.injection"))
+	if s.entry.Repeat && !a.InjectionDetected {
+		farmStyle := lipgloss.NewStyle().Foreground(ui.ColorWarning).Bold(true)
+		content += farmStyle.Render(i18n.T("adventure.repeat")) + "\n"
+	}
+	content += renderDMComment(a.DMComment, ctx.Persona, a.Offline)
 
-	content := a.OutcomeDescription +
-		"\n\n" +
-		fmt.Sprintf("Score: %s  |  Damage: %d", scoreText, a.DamageDealt) +
-		"\n" +
-		a.DMComment +
-		"\n\nPress [Enter] to continue..."
+	content += "───────────────────────────────────────────\n\n"
+	content += renderAdventureStatus(ctx) + "\n\n"
+	content += ui.StyleHelp.Render(i18n.T("adventure.result_help"))
 
-	return ui.CenteredView("ASSESSMENT", content, true, ctx.Width, ctx.Height)
+	return ui.CenteredView(i18n.T("assessment.title"), content, true, ctx.Width, ctx.Height)
 }
@@ -0,0 +1,248 @@
+package states
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erwaen/type-glish/internal/config"
+	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
+	"github.com/erwaen/type-glish/internal/review"
+	"github.com/erwaen/type-glish/internal/runlog"
+	"github.com/erwaen/type-glish/internal/ui"
+)
+
+const (
+	memoryWraithChance  = 0.25 // Chance of a Memory Wraith after a victory, if cards are due
+	memoryWraithCards   = 3    // Maximum review cards in a Memory Wraith encounter
+	memoryWraithCounter = 8    // Base damage the wraith deals for a wrong answer
+)
+
+// ReviewState asks the player to retype their past mistakes correctly.
+// It runs either as the "Review" menu mode or as a Memory Wraith encounter.
+type ReviewState struct {
+	textInput textinput.Model
+	cfg       *config.Config
+	queue     []*review.Card
+	reviewed  map[*review.Card]bool // Cards already scheduled this session
+	wraith    bool
+
+	answered   bool // Showing feedback for the current card
+	lastAnswer string
+	quality    int
+	damage     int // Damage dealt (wraith) or received (negative) on the last answer
+
+	firstTry int // Cards answered correctly on the first attempt
+	total    int // Distinct cards in the session
+}
+
+func newReviewInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = i18n.T("review.placeholder")
+	ti.Focus()
+	ti.CharLimit = 200
+	ti.Width = 50
+	return ti
+}
+
+// NewReviewState starts a review session with the given due cards
+func NewReviewState(cfg *config.Config, cards []*review.Card) *ReviewState {
+	return &ReviewState{
+		textInput: newReviewInput(),
+		cfg:       cfg,
+		queue:     cards,
+		reviewed:  make(map[*review.Card]bool),
+		total:     len(cards),
+	}
+}
+
+// NewMemoryWraithState starts a Memory Wraith encounter with the most
+// overdue review cards
+func NewMemoryWraithState(ctx *game.Context, cfg *config.Config) *ReviewState {
+	cards := ctx.Deck.Due(time.Now())
+	if len(cards) > memoryWraithCards {
+		cards = cards[:memoryWraithCards]
+	}
+
+	ctx.CurrentEnemy = game.NewMemoryWraith(len(cards))
+	ctx.ClearEffects()
+	ctx.CurrentNarrative = i18n.T("review.wraith_rises", i18n.T("review.wraith_name"), i18n.T("review.wraith_description"))
+
+	s := NewReviewState(cfg, cards)
+	s.wraith = true
+	return s
+}
+
+func (s *ReviewState) Init(ctx *game.Context) tea.Cmd {
+	return textinput.Blink
+}
+
+func (s *ReviewState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			return s, tea.Quit
+		case tea.KeyEsc:
+			return NewMenuState(s.cfg), nil
+		case tea.KeyEnter:
+			if len(s.queue) == 0 {
+				return NewMenuState(s.cfg), nil
+			}
+			if !s.answered {
+				if s.textInput.Value() == "" {
+					return s, nil
+				}
+				s.answer(ctx)
+				return s, nil
+			}
+			return s.next(ctx)
+		}
+	}
+
+	if s.answered {
+		return s, nil
+	}
+
+	s.textInput, cmd = s.textInput.Update(msg)
+	return s, cmd
+}
+
+// answer grades the typed sentence against the current card
+func (s *ReviewState) answer(ctx *game.Context) {
+	card := s.queue[0]
+	s.lastAnswer = s.textInput.Value()
+	s.quality = review.Grade(s.lastAnswer, card)
+	s.answered = true
+
+	// Only the first attempt counts for scheduling
+	if !s.reviewed[card] {
+		s.reviewed[card] = true
+		if s.quality >= 3 {
+			s.firstTry++
+		}
+		card.Review(s.quality, time.Now())
+		if err := ctx.Deck.Save(); err != nil {
+			log.Printf("Error saving review deck: %v", err)
+		}
+	}
+
+	s.damage = 0
+	if s.wraith && ctx.CurrentEnemy != nil {
+		if s.quality >= 3 {
+			s.damage = game.MemoryWraithDamage
+			ctx.CurrentEnemy.HP = max(0, ctx.CurrentEnemy.HP-s.damage)
+		} else {
+			s.damage = -game.ScaleCounterDamage(memoryWraithCounter, ctx.Level())
+			ctx.Stats.HP = max(0, ctx.Stats.HP+s.damage)
+		}
+
+		ctx.RecordTurn(runlog.Turn{
+			Kind:           runlog.KindReview,
+			Input:          s.lastAnswer,
+			Corrected:      card.Corrected,
+			Score:          s.quality * 2, // SM-2 quality is 0-5, turns are scored 0-10
+			DamageDealt:    max(0, s.damage),
+			DamageReceived: max(0, -s.damage),
+		})
+	}
+}
+
+// next moves on to the following card, re-queueing missed ones
+func (s *ReviewState) next(ctx *game.Context) (GameState, tea.Cmd) {
+	card := s.queue[0]
+	s.queue = s.queue[1:]
+	if s.quality < 3 {
+		s.queue = append(s.queue, card)
+	}
+
+	if s.wraith {
+		if ctx.Stats.HP <= 0 {
+			return &GameOverState{}, nil
+		}
+		if ctx.CurrentEnemy != nil && ctx.CurrentEnemy.HP <= 0 {
+			return &VictoryState{cfg: s.cfg}, nil
+		}
+	}
+
+	s.answered = false
+	s.textInput = newReviewInput()
+	return s, textinput.Blink
+}
+
+func (s *ReviewState) View(ctx *game.Context) string {
+	title := i18n.T("review.title")
+	if s.wraith {
+		title = i18n.T("review.wraith_title")
+	}
+
+	var content string
+
+	if s.wraith && ctx.CurrentEnemy != nil {
+		enemy := ctx.CurrentEnemy
+		name := i18n.T("review.wraith_name")
+		content += ui.RenderStatusBar(ctx.Stats.HP, 100, ctx.Stats.Gold, ctx.Stats.XP) + "\n\n"
+		content += ui.RenderCombatHeader(i18n.T("review.wraith_location"), name) + "\n\n"
+		content += ui.RenderHPBar(enemy.HP, enemy.MaxHP, name, 20) + "\n\n"
+		content += ui.StyleSubTitle.Render(i18n.T("common.dm")+" "+i18n.T("review.wraith_description")) + "\n\n"
+	}
+
+	if len(s.queue) == 0 {
+		if s.total == 0 {
+			content += i18n.T("review.empty") + "\n\n"
+		} else {
+			content += ui.StyleSubTitle.Render(i18n.T("review.complete")) + "\n\n"
+			content += i18n.T("review.first_try", s.firstTry, s.total) + "\n\n"
+		}
+		content += ui.StyleHelp.Render(i18n.T("common.back_help"))
+		return ui.CenteredView(title, content, true, ctx.Width, ctx.Height)
+	}
+
+	card := s.queue[0]
+
+	content += ui.StyleSubTitle.Render(i18n.T("review.old_mistake", len(s.queue))) + "\n"
+	content += fmt.Sprintf("> %s\n\n", card.Original)
+
+	content += "───────────────────────────────────────────\n\n"
+
+	if !s.answered {
+		content += i18n.T("review.retype") + "\n"
+		content += s.textInput.View() + "\n\n"
+		content += ui.StyleHelp.Render(i18n.T("review.help"))
+		return ui.CenteredView(title, content, true, ctx.Width, ctx.Height)
+	}
+
+	content += ui.StyleSubTitle.Render(i18n.T("review.you_typed")) + "\n"
+	content += fmt.Sprintf("> %s\n\n", s.lastAnswer)
+	content += ui.StyleSubTitle.Render(i18n.T("review.correct")) + "\n"
+	content += fmt.Sprintf("> %s\n\n", card.Corrected)
+
+	switch {
+	case s.quality == 5:
+		content += ui.StyleDamageDealt.Render(i18n.T("review.perfect")) + "\n"
+	case s.quality == 4:
+		content += ui.StyleDamageDealt.Render(i18n.T("review.punctuation")) + "\n"
+	case s.quality == 3:
+		content += ui.StyleDamageDealt.Render(i18n.T("review.almost")) + "\n"
+	default:
+		content += ui.StyleDamageReceived.Render(i18n.T("review.again")) + "\n"
+	}
+
+	if s.damage > 0 {
+		content += i18n.T("review.dealt", ui.StyleDamageDealt.Render(i18n.T("common.dmg", s.damage))) + "\n"
+	} else if s.damage < 0 {
+		content += i18n.T("review.took", ui.StyleDamageReceived.Render(i18n.T("common.dmg", -s.damage))) + "\n"
+	}
+	if s.quality >= 3 {
+		content += ui.StyleSubTitle.Render(i18n.T("review.next", card.Interval)) + "\n"
+	}
+
+	content += "\n" + ui.StyleHelp.Render(i18n.T("common.continue_help"))
+
+	return ui.CenteredView(title, content, true, ctx.Width, ctx.Height)
+}
@@ -1,12 +1,36 @@
 package states
 
 import (
+	"fmt"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erwaen/type-glish/internal/cefr"
 	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
+	"github.com/erwaen/type-glish/internal/llm"
 	"github.com/erwaen/type-glish/internal/ui"
 )
 
+// Settings menu entries, also used as message catalog keys
+const (
+	settingLlamaCpp   = "settings.llamacpp"
+	settingGemini     = "settings.gemini"
+	settingGeminiKey  = "settings.gemini_key"
+	settingDifficulty = "settings.difficulty"
+	settingAdaptive   = "settings.adaptive"
+	settingLanguage   = "settings.explanation_language"
+	settingLocale     = "settings.locale"
+	settingPersona    = "settings.persona"
+	settingWarmup     = "settings.warmup"
+	settingGenerated  = "settings.generate_enemies"
+	settingNarration  = "settings.narration"
+	settingCache      = "settings.cache"
+	settingBudget     = "settings.budget"
+	settingUsage      = "settings.usage"
+	settingBack       = "settings.back"
+)
+
 // SettingsState is the Provider Selection Menu
 type SettingsState struct {
 	choices []string
@@ -16,7 +40,7 @@ type SettingsState struct {
 
 func NewSettingsState(cfg *config.Config) *SettingsState {
 	return &SettingsState{
-		choices: []string{"Use llama.cpp (Local)", "Use Gemini (Cloud)", "Update Gemini API Key", "Back"},
+		choices: []string{settingLlamaCpp, settingGemini, settingGeminiKey, settingDifficulty, settingAdaptive, settingLanguage, settingLocale, settingPersona, settingWarmup, settingGenerated, settingNarration, settingCache, settingBudget, settingUsage, settingBack},
 		cursor:  0,
 		cfg:     cfg,
 	}
@@ -42,25 +66,66 @@ func (s *SettingsState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.C
 			if s.cursor < len(s.choices)-1 {
 				s.cursor++
 			}
+		case "left", "right", "h", "l":
+			backwards := msg.String() == "left" || msg.String() == "h"
+			switch s.choices[s.cursor] {
+			case settingDifficulty:
+				s.cycleDifficulty(ctx, backwards)
+			case settingLanguage:
+				s.cycleLanguage(ctx, backwards)
+			case settingLocale:
+				s.cycleLocale(backwards)
+			case settingPersona:
+				s.cyclePersona(ctx, backwards)
+			case settingBudget:
+				s.cycleBudget(backwards)
+			}
 		case "enter":
-			if s.cursor == 0 {
-				// Llama
+			switch s.choices[s.cursor] {
+			case settingLlamaCpp:
 				s.cfg.Provider = "llamacpp"
 				config.SaveConfig(s.cfg)
 				return NewMenuState(s.cfg), nil
-			} else if s.cursor == 1 {
-				// Gemini
+			case settingGemini:
 				if s.cfg.GeminiAPIKey == "" {
 					return NewAPIInputState(s.cfg), nil
 				}
 				s.cfg.Provider = "gemini"
 				config.SaveConfig(s.cfg)
 				return NewMenuState(s.cfg), nil
-			} else if s.cursor == 2 {
-				// Update Gemini Key
+			case settingGeminiKey:
 				return NewAPIInputState(s.cfg), nil
-			} else if s.cursor == 3 {
-				// Back
+			case settingDifficulty:
+				s.cycleDifficulty(ctx, false)
+			case settingAdaptive:
+				s.cfg.AdaptiveDifficulty = !s.cfg.AdaptiveDifficulty
+				ctx.AdaptiveEnabled = s.cfg.AdaptiveDifficulty
+				config.SaveConfig(s.cfg)
+			case settingLanguage:
+				s.cycleLanguage(ctx, false)
+			case settingLocale:
+				s.cycleLocale(false)
+			case settingPersona:
+				s.cyclePersona(ctx, false)
+			case settingWarmup:
+				s.cfg.TypingWarmup = !s.cfg.TypingWarmup
+				config.SaveConfig(s.cfg)
+			case settingGenerated:
+				s.cfg.GenerateEnemies = !s.cfg.GenerateEnemies
+				ctx.GenerateEnemies = s.cfg.GenerateEnemies
+				config.SaveConfig(s.cfg)
+			case settingNarration:
+				s.cfg.DisableNarration = !s.cfg.DisableNarration
+				ctx.Narration = !s.cfg.DisableNarration
+				config.SaveConfig(s.cfg)
+			case settingCache:
+				s.cfg.DisableCache = !s.cfg.DisableCache
+				config.SaveConfig(s.cfg)
+			case settingBudget:
+				s.cycleBudget(false)
+			case settingUsage:
+				return NewUsageState(s.cfg), nil
+			case settingBack:
 				return NewMenuState(s.cfg), nil
 			}
 		}
@@ -71,13 +136,154 @@ func (s *SettingsState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.C
 func (s *SettingsState) View(ctx *game.Context) string {
 	var content string
 
-	content += ui.StyleSubTitle.Render("Select your Intelligence Provider") + "\n\n"
+	content += ui.StyleSubTitle.Render(i18n.T("settings.subtitle")) + "\n\n"
 
 	for i, choice := range s.choices {
-		content += ui.RenderMenuItem(choice, s.cursor == i) + "\n"
+		label := i18n.T(choice)
+		switch choice {
+		case settingDifficulty:
+			level := cefr.Parse(s.cfg.Difficulty)
+			label = fmt.Sprintf("%s: %s (%s)", label, level, levelLabel(level))
+		case settingAdaptive:
+			label = fmt.Sprintf("%s: %s", label, onOff(s.cfg.AdaptiveDifficulty))
+		case settingLanguage:
+			language := s.cfg.ExplanationLanguage
+			if language == "" {
+				language = i18n.T("common.off")
+			}
+			label = fmt.Sprintf("%s: %s", label, language)
+		case settingLocale:
+			label = fmt.Sprintf("%s: %s", label, i18n.T("locale.name"))
+		case settingPersona:
+			label = fmt.Sprintf("%s: %s", label, personaName(s.cfg.Persona))
+		case settingWarmup:
+			label = fmt.Sprintf("%s: %s", label, onOff(s.cfg.TypingWarmup))
+		case settingGenerated:
+			label = fmt.Sprintf("%s: %s", label, onOff(s.cfg.GenerateEnemies))
+			if s.cfg.GenerateEnemies {
+				label += " " + i18n.T("settings.bestiary_size", ctx.Bestiary.Len())
+			}
+		case settingNarration:
+			label = fmt.Sprintf("%s: %s", label, onOff(!s.cfg.DisableNarration))
+		case settingCache:
+			label = fmt.Sprintf("%s: %s", label, onOff(!s.cfg.DisableCache))
+		case settingBudget:
+			budget := i18n.T("common.off")
+			if s.cfg.DailyTokenBudget > 0 {
+				budget = i18n.T("settings.budget_tokens", formatTokens(s.cfg.DailyTokenBudget))
+			}
+			label = fmt.Sprintf("%s: %s", label, budget)
+		}
+		content += ui.RenderMenuItem(label, s.cursor == i) + "\n"
 	}
 
-	content += ui.StyleHelp.Render("\n(Use ↑/↓ to move, Enter to select, q to quit)")
+	content += ui.StyleHelp.Render("\n" + i18n.T("settings.help"))
+
+	return ui.CenteredView(i18n.T("settings.title"), content, true, ctx.Width, ctx.Height)
+}
+
+// cycleDifficulty moves to the next (or previous) CEFR band and saves it
+func (s *SettingsState) cycleDifficulty(ctx *game.Context, backwards bool) {
+	level := cefr.Parse(s.cfg.Difficulty)
+	if backwards {
+		level = level.Prev()
+	} else {
+		level = level.Next()
+	}
+	s.cfg.Difficulty = string(level)
+	ctx.Difficulty = level
+	config.SaveConfig(s.cfg)
+}
+
+// cycleLanguage moves to the next (or previous) explanation language and saves it
+func (s *SettingsState) cycleLanguage(ctx *game.Context, backwards bool) {
+	languages := config.ExplanationLanguages
+	idx := 0
+	for i, l := range languages {
+		if l == s.cfg.ExplanationLanguage {
+			idx = i
+		}
+	}
+	if backwards {
+		idx = (idx + len(languages) - 1) % len(languages)
+	} else {
+		idx = (idx + 1) % len(languages)
+	}
+	s.cfg.ExplanationLanguage = languages[idx]
+	ctx.ExplanationLanguage = languages[idx]
+	config.SaveConfig(s.cfg)
+}
+
+// cycleLocale moves to the next (or previous) interface language and saves it
+func (s *SettingsState) cycleLocale(backwards bool) {
+	locales := i18n.Locales()
+	idx := 0
+	for i, l := range locales {
+		if l == i18n.Locale() {
+			idx = i
+		}
+	}
+	if backwards {
+		idx = (idx + len(locales) - 1) % len(locales)
+	} else {
+		idx = (idx + 1) % len(locales)
+	}
+	i18n.SetLocale(locales[idx])
+	s.cfg.Locale = locales[idx]
+	config.SaveConfig(s.cfg)
+}
+
+// cyclePersona moves to the next (or previous) DM persona and saves it
+func (s *SettingsState) cyclePersona(ctx *game.Context, backwards bool) {
+	personas := llm.Personas
+	current := llm.GetPersona(s.cfg.Persona).ID
+	idx := 0
+	for i, p := range personas {
+		if p.ID == current {
+			idx = i
+		}
+	}
+	if backwards {
+		idx = (idx + len(personas) - 1) % len(personas)
+	} else {
+		idx = (idx + 1) % len(personas)
+	}
+	s.cfg.Persona = personas[idx].ID
+	ctx.Persona = personas[idx].ID
+	config.SaveConfig(s.cfg)
+}
+
+// cycleBudget moves to the next (or previous) daily token budget and saves it
+func (s *SettingsState) cycleBudget(backwards bool) {
+	budgets := config.DailyTokenBudgets
+	idx := 0
+	for i, b := range budgets {
+		if b == s.cfg.DailyTokenBudget {
+			idx = i
+		}
+	}
+	if backwards {
+		idx = (idx + len(budgets) - 1) % len(budgets)
+	} else {
+		idx = (idx + 1) % len(budgets)
+	}
+	s.cfg.DailyTokenBudget = budgets[idx]
+	config.SaveConfig(s.cfg)
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return i18n.T("common.on")
+	}
+	return i18n.T("common.off")
+}
+
+// personaName returns the localized name of a DM persona
+func personaName(id string) string {
+	return i18n.T("persona." + llm.GetPersona(id).ID)
+}
 
-	return ui.CenteredView("SETTINGS", content, true, ctx.Width, ctx.Height)
+// levelLabel returns the localized name of a CEFR band
+func levelLabel(level cefr.Level) string {
+	return i18n.T("level." + string(level))
 }
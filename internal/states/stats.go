@@ -0,0 +1,75 @@
+package states
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erwaen/type-glish/internal/config"
+	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
+	"github.com/erwaen/type-glish/internal/ui"
+)
+
+// StatsState shows the player stats and the adaptive difficulty level
+type StatsState struct {
+	cfg *config.Config
+}
+
+func NewStatsState(cfg *config.Config) *StatsState {
+	return &StatsState{cfg: cfg}
+}
+
+func (s *StatsState) Init(ctx *game.Context) tea.Cmd {
+	return nil
+}
+
+func (s *StatsState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return s, tea.Quit
+		case "enter", "esc", "q":
+			return NewMenuState(s.cfg), nil
+		}
+	}
+	return s, nil
+}
+
+func (s *StatsState) View(ctx *game.Context) string {
+	var content string
+
+	content += ui.RenderStatusBar(ctx.Stats.HP, 100, ctx.Stats.Gold, ctx.Stats.XP) + "\n\n"
+
+	content += ui.StyleSubTitle.Render(i18n.T("stats.difficulty")) + "\n"
+	content += i18n.T("stats.configured_level", ctx.Difficulty, levelLabel(ctx.Difficulty)) + "\n"
+
+	if !ctx.AdaptiveEnabled {
+		content += i18n.T("stats.adaptive", onOff(false)) + "\n\n"
+	} else {
+		level := ctx.Level()
+		minTier, maxTier := ctx.Adaptive.TierRange()
+		content += i18n.T("stats.adaptive", onOff(true)) + "\n"
+		content += i18n.T("stats.adaptive_level", level, levelLabel(level), ctx.Adaptive.Shift) + "\n"
+		content += i18n.T("stats.enemy_tiers", minTier, maxTier) + "\n\n"
+	}
+
+	content += "───────────────────────────────────────────\n\n"
+	content += ui.StyleSubTitle.Render(i18n.T("stats.recent_scores")) + "\n"
+	if len(ctx.Adaptive.Scores) == 0 {
+		content += i18n.T("stats.no_scores") + "\n\n"
+	} else {
+		scores := make([]string, len(ctx.Adaptive.Scores))
+		for i, score := range ctx.Adaptive.Scores {
+			scores[i] = fmt.Sprintf("%d", score)
+		}
+		content += strings.Join(scores, "  ") + "\n"
+		content += i18n.T("stats.success_rate",
+			int(ctx.Adaptive.SuccessRate()*100), int(game.AdaptiveTargetRate*100), game.AdaptiveSuccessScore) + "\n\n"
+	}
+
+	content += ui.StyleHelp.Render(i18n.T("common.back_help"))
+
+	return ui.CenteredView(i18n.T("stats.title"), content, true, ctx.Width, ctx.Height)
+}
@@ -0,0 +1,186 @@
+package states
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erwaen/type-glish/internal/config"
+	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
+	"github.com/erwaen/type-glish/internal/runlog"
+	"github.com/erwaen/type-glish/internal/typing"
+	"github.com/erwaen/type-glish/internal/ui"
+)
+
+const warmupPhrases = 3 // Phrases to retype in the warm-up
+
+// maxTypedBurst is the most runes one key message carries when typed
+const maxTypedBurst = 3
+
+// TypingState is the typing challenge: the player retypes target phrases
+// while WPM and accuracy are measured. As a warm-up it runs before the
+// first fight; as a quick strike it deals bonus damage to the enemy.
+type TypingState struct {
+	cfg         *config.Config
+	quickStrike bool
+	phrases     []string
+	current     int
+	session     *typing.Session
+
+	// Totals across all phrases
+	wpmSum   float64
+	accSum   float64
+	finished bool
+	damage   int // Quick strike bonus damage
+}
+
+// NewWarmupState starts the typing warm-up before the first fight
+func NewWarmupState(cfg *config.Config) *TypingState {
+	s := &TypingState{cfg: cfg, phrases: typing.RandomPhrases(warmupPhrases)}
+	s.session = typing.NewSession(s.phrases[0])
+	return s
+}
+
+// NewQuickStrikeState starts a quick strike against the current enemy
+func NewQuickStrikeState(cfg *config.Config) *TypingState {
+	s := &TypingState{cfg: cfg, quickStrike: true, phrases: typing.RandomPhrases(1)}
+	s.session = typing.NewSession(s.phrases[0])
+	return s
+}
+
+func (s *TypingState) Init(ctx *game.Context) tea.Cmd {
+	// The clock starts when the first phrase is shown
+	s.session.Start(time.Now())
+	return nil
+}
+
+func (s *TypingState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return s, nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyCtrlC:
+		return s, tea.Quit
+	case tea.KeyEsc:
+		// Skip the warm-up, or give up the quick strike
+		return NewCombatState(s.cfg), nil
+	case tea.KeyEnter:
+		if s.finished {
+			return s.leave(ctx)
+		}
+	case tea.KeyBackspace:
+		s.session.Backspace()
+	case tea.KeySpace:
+		s.typeRunes([]rune{' '}, false, ctx)
+	case tea.KeyRunes:
+		// Bracketed paste is flagged; without it, a burst of runes read at
+		// once is a paste too, as fast typing delivers a couple at most
+		s.typeRunes(keyMsg.Runes, keyMsg.Paste || len(keyMsg.Runes) > maxTypedBurst, ctx)
+	}
+	return s, nil
+}
+
+// typeRunes feeds characters to the session and moves to the next phrase
+// when the current one is done
+func (s *TypingState) typeRunes(runes []rune, pasted bool, ctx *game.Context) {
+	if s.finished {
+		return
+	}
+	now := time.Now()
+	if pasted {
+		s.session.Paste(runes, now)
+	} else {
+		for _, r := range runes {
+			s.session.Type(r, now)
+		}
+	}
+	if !s.session.Done() {
+		return
+	}
+
+	s.wpmSum += s.session.WPM(now)
+	s.accSum += s.session.Accuracy()
+	s.current++
+
+	if s.current < len(s.phrases) {
+		s.session = typing.NewSession(s.phrases[s.current])
+		s.session.Start(now)
+		return
+	}
+
+	s.finished = true
+	if s.quickStrike && ctx.CurrentEnemy != nil {
+		s.damage = game.QuickStrikeDamage(s.averageWPM(), s.averageAccuracy())
+		ctx.CurrentEnemy.HP = max(0, ctx.CurrentEnemy.HP-s.damage)
+
+		ctx.RecordTurn(runlog.Turn{
+			Kind:        runlog.KindQuickStrike,
+			Input:       string(s.session.Typed),
+			Corrected:   string(s.session.Target),
+			Score:       int(s.averageAccuracy() * 10),
+			DamageDealt: s.damage,
+		})
+	}
+}
+
+// leave goes back to the fight, or to victory if the strike finished the enemy
+func (s *TypingState) leave(ctx *game.Context) (GameState, tea.Cmd) {
+	if s.quickStrike && ctx.CurrentEnemy != nil && ctx.CurrentEnemy.HP <= 0 {
+		return &VictoryState{cfg: s.cfg}, nil
+	}
+	return NewCombatState(s.cfg), nil
+}
+
+func (s *TypingState) averageWPM() float64 {
+	return s.wpmSum / float64(max(1, s.current))
+}
+
+func (s *TypingState) averageAccuracy() float64 {
+	return s.accSum / float64(max(1, s.current))
+}
+
+func (s *TypingState) View(ctx *game.Context) string {
+	title := i18n.T("typing.warmup_title")
+	if s.quickStrike {
+		title = i18n.T("typing.quickstrike_title")
+	}
+
+	var content string
+
+	if s.quickStrike && ctx.CurrentEnemy != nil {
+		content += ui.RenderHPBar(ctx.CurrentEnemy.HP, ctx.CurrentEnemy.MaxHP, ctx.CurrentEnemy.Name, 20) + "\n\n"
+	}
+
+	if s.finished {
+		content += ui.StyleSubTitle.Render(i18n.T("typing.done")) + "\n\n"
+		content += i18n.T("typing.stats", s.averageWPM(), int(s.averageAccuracy()*100)) + "\n\n"
+		if s.quickStrike {
+			if s.damage > 0 {
+				content += i18n.T("review.dealt", ui.StyleDamageDealt.Render(i18n.T("common.dmg", s.damage))) + "\n\n"
+			} else {
+				content += ui.StyleDamageReceived.Render(i18n.T("typing.missed", int(game.QuickStrikeMinAccuracy*100))) + "\n\n"
+			}
+		}
+		content += ui.StyleHelp.Render(i18n.T("common.continue_help"))
+		return ui.CenteredView(title, content, true, ctx.Width, ctx.Height)
+	}
+
+	if s.quickStrike {
+		content += i18n.T("typing.quickstrike_intro") + "\n\n"
+	} else {
+		content += i18n.T("typing.warmup_intro") + "\n\n"
+	}
+
+	content += ui.StyleSubTitle.Render(i18n.T("typing.phrase", s.current+1, len(s.phrases))) + "\n"
+	content += "> " + ui.RenderTypingTarget(s.session.Target, s.session.Typed) + "\n\n"
+
+	now := time.Now()
+	content += fmt.Sprintf("%s\n\n", i18n.T("typing.stats", s.session.WPM(now), int(s.session.Accuracy()*100)))
+
+	content += ui.StyleHelp.Render(i18n.T("typing.help"))
+
+	return ui.CenteredView(title, content, true, ctx.Width, ctx.Height)
+}
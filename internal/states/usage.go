@@ -0,0 +1,141 @@
+package states
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erwaen/type-glish/internal/config"
+	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
+	"github.com/erwaen/type-glish/internal/llm"
+	"github.com/erwaen/type-glish/internal/ui"
+)
+
+// UsageState shows the token usage and estimated cost of the LLM providers
+type UsageState struct {
+	cfg *config.Config
+}
+
+func NewUsageState(cfg *config.Config) *UsageState {
+	return &UsageState{cfg: cfg}
+}
+
+func (s *UsageState) Init(ctx *game.Context) tea.Cmd {
+	return nil
+}
+
+func (s *UsageState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return s, tea.Quit
+		case "enter", "esc", "q":
+			return NewSettingsState(s.cfg), nil
+		}
+	}
+	return s, nil
+}
+
+func (s *UsageState) View(ctx *game.Context) string {
+	var content string
+
+	sections := []struct {
+		title string
+		usage map[string]llm.Usage
+	}{
+		{i18n.T("usage.session"), ctx.Usage.Session()},
+		{i18n.T("usage.today"), ctx.Usage.Today()},
+		{i18n.T("usage.lifetime"), ctx.Usage.LifetimeUsage()},
+	}
+
+	providers := ctx.Usage.ProviderNames()
+	for _, section := range sections {
+		content += ui.StyleSubTitle.Render(section.title) + "\n"
+		shown := 0
+		for _, name := range providers {
+			u, ok := section.usage[name]
+			if !ok {
+				continue
+			}
+			content += s.renderUsage(name, u) + "\n"
+			shown++
+		}
+		if shown == 0 {
+			content += i18n.T("usage.none") + "\n"
+		}
+		content += "\n"
+	}
+
+	content += "───────────────────────────────────────────\n\n"
+	content += ui.StyleSubTitle.Render(i18n.T("usage.latency")) + "\n"
+	metrics := ctx.Metrics.Snapshot()
+	if len(metrics) == 0 {
+		content += i18n.T("usage.none") + "\n"
+	}
+	for _, pm := range metrics {
+		content += i18n.T("usage.latency_line", pm.Name, pm.Calls, pm.Errors, pm.MeanLatency().Round(time.Millisecond)) + "\n"
+		content += renderHistogram(pm.Buckets) + "\n"
+	}
+	content += "\n"
+
+	content += ui.StyleSubTitle.Render(i18n.T("usage.budget")) + "\n"
+	if s.cfg.DailyTokenBudget == 0 {
+		content += i18n.T("usage.no_budget") + "\n\n"
+	} else {
+		spent := 0
+		for name, u := range ctx.Usage.Today() {
+			if llm.IsPaid(name) {
+				spent += u.Total()
+			}
+		}
+		content += i18n.T("usage.budget_line", formatTokens(spent), formatTokens(s.cfg.DailyTokenBudget)) + "\n"
+		if ctx.Meter != nil && ctx.Meter.OverBudget() {
+			content += ui.StyleDamageReceived.Render(i18n.T("usage.over_budget")) + "\n"
+		}
+		content += "\n"
+	}
+
+	content += ui.StyleHelp.Render(i18n.T("common.back_help"))
+
+	return ui.CenteredView(i18n.T("usage.title"), content, true, ctx.Width, ctx.Height)
+}
+
+// renderUsage renders one provider's usage, with the cost if it is paid
+func (s *UsageState) renderUsage(name string, u llm.Usage) string {
+	line := i18n.T("usage.line", name, u.Calls, formatTokens(u.PromptTokens), formatTokens(u.CompletionTokens))
+	if llm.IsPaid(name) {
+		input, output := s.cfg.GeminiPrices()
+		line += fmt.Sprintf("  ~$%.4f", u.Cost(input, output))
+	}
+	return line
+}
+
+// renderHistogram renders latency buckets as one line of labelled bars,
+// skipping empty buckets
+func renderHistogram(buckets []int) string {
+	peak := slices.Max(buckets)
+	var parts []string
+	for i, n := range buckets {
+		if n == 0 {
+			continue
+		}
+		bar := strings.Repeat("█", max(1, n*8/peak))
+		parts = append(parts, fmt.Sprintf("%s %s %d", llm.BucketLabel(i), bar, n))
+	}
+	return "  " + strings.Join(parts, "  ")
+}
+
+// formatTokens renders a token count as 950, 12.3k or 1.2M
+func formatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	}
+	return fmt.Sprint(n)
+}
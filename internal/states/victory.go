@@ -3,13 +3,17 @@ package states
 import (
 	"fmt"
 	"math/rand"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erwaen/type-glish/internal/config"
 	"github.com/erwaen/type-glish/internal/game"
+	"github.com/erwaen/type-glish/internal/i18n"
 	"github.com/erwaen/type-glish/internal/ui"
 )
 
 type VictoryState struct {
+	cfg           *config.Config
 	defeatedEnemy string
 	goldEarned    int
 }
@@ -36,16 +40,22 @@ func (s *VictoryState) Update(msg tea.Msg, ctx *game.Context) (GameState, tea.Cm
 			// Award XP and Gold
 			ctx.Stats.XP += 10
 			ctx.Stats.Gold += s.goldEarned
+			ctx.RecordVictory()
+
+			// Sometimes the player's old mistakes come back to haunt them
+			if len(ctx.Deck.Due(time.Now())) > 0 && rand.Float32() < memoryWraithChance {
+				return NewMemoryWraithState(ctx, s.cfg), nil
+			}
 
 			// 50% chance: new combat or path choice
 			if rand.Float32() < 0.5 {
 				// New combat with random enemy
-				ctx.CurrentEnemy = game.RandomEnemy()
-				ctx.CurrentNarrative = fmt.Sprintf("A %s appears! %s", ctx.CurrentEnemy.Name, ctx.CurrentEnemy.Description)
-				return NewCombatState(), nil
+				enemy := ctx.SpawnEnemy()
+				return sceneTransition(ctx, game.Scene{Kind: game.SceneEncounter, Enemy: enemy}, NewCombatState(s.cfg)), nil
 			} else {
 				// Path choice for healing opportunity
-				return NewPathChoiceState(), nil
+				paths := NewPathChoiceState(s.cfg)
+				return sceneTransition(ctx, game.Scene{Kind: game.SceneCrossroads, Paths: paths.pathDescriptions()}, paths), nil
 			}
 		}
 		if msg.Type == tea.KeyCtrlC {
@@ -59,24 +69,16 @@ func (s *VictoryState) View(ctx *game.Context) string {
 	// Gold display styling
 	goldStyle := ui.StyleDamageDealt
 
-	content := fmt.Sprintf(`
-    ╔═══════════════════════════════════════╗
-    ║                                       ║
-    ║          V I C T O R Y !              ║
-    ║                                       ║
-    ║   You have defeated the %s!
-    ║                                       ║
-    ║   Your mastery of grammar prevails.   ║
-    ║                                       ║
-    ╚═══════════════════════════════════════╝
-`, s.defeatedEnemy)
+	content := ui.RenderBanner(i18n.T("victory.banner"),
+		i18n.T("victory.defeated", s.defeatedEnemy),
+		"",
+		i18n.T("victory.line"))
 
 	content += "\n"
-	content += fmt.Sprintf("    +10 XP    %s\n\n", goldStyle.Render(fmt.Sprintf("+%d Gold", s.goldEarned)))
+	content += fmt.Sprintf("    %s    %s\n\n", i18n.T("victory.xp", 10), goldStyle.Render(i18n.T("victory.gold", s.goldEarned)))
 	content += "───────────────────────────────────────────\n\n"
 	content += ui.RenderStatusBar(ctx.Stats.HP, 100, ctx.Stats.Gold+s.goldEarned, ctx.Stats.XP+10) + "\n\n"
-	content += ui.StyleHelp.Render("Press [Enter] to continue your journey...")
+	content += ui.StyleHelp.Render(i18n.T("victory.help"))
 
-	return ui.CenteredView("VICTORY", content, true, ctx.Width, ctx.Height)
+	return ui.CenteredView(i18n.T("victory.title"), content, true, ctx.Width, ctx.Height)
 }
-
@@ -0,0 +1,118 @@
+package textsim
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Normalize lowercases a sentence and collapses whitespace
+func Normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// StripPunctuation removes punctuation and collapses whitespace
+func StripPunctuation(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Levenshtein returns the edit distance between two strings, in runes
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// OSADistance is like Levenshtein but also counts swapping two adjacent
+// runes as a single edit, which matches how typos are usually made
+func OSADistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// Similarity returns 1 minus the edit distance normalized by the longer
+// string, so 1 means identical and 0 means nothing in common
+func Similarity(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(longest)
+}
+
+// WordNGrams returns the set of word n-grams of a sentence. Sentences
+// shorter than n words yield a single n-gram of the whole sentence.
+func WordNGrams(s string, n int) map[string]bool {
+	words := strings.Fields(s)
+	grams := make(map[string]bool)
+	if len(words) == 0 {
+		return grams
+	}
+	if len(words) < n {
+		grams[strings.Join(words, " ")] = true
+		return grams
+	}
+	for i := 0; i+n <= len(words); i++ {
+		grams[strings.Join(words[i:i+n], " ")] = true
+	}
+	return grams
+}
+
+// NGramOverlap returns the share of word n-grams the two sentences have in
+// common, relative to the longer one. A copied sentence with a word or two
+// added still scores high, while a short opener shared by a longer
+// sentence does not.
+func NGramOverlap(a, b string, n int) float64 {
+	ga, gb := WordNGrams(a, n), WordNGrams(b, n)
+	if len(ga) == 0 || len(gb) == 0 {
+		return 0
+	}
+	largest := max(len(ga), len(gb))
+	shared := 0
+	for g := range ga {
+		if gb[g] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(largest)
+}
@@ -0,0 +1,123 @@
+package textsim
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", ""},
+		{"  I  Attack\tthe GOBLIN \n", "i attack the goblin"},
+		{"Hello, World!", "hello, world!"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStripPunctuation(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", ""},
+		{"Hello, world!", "Hello world"},
+		{"I don't know... really?", "I dont know really"},
+		{" - ", ""},
+	}
+	for _, tt := range tests {
+		if got := StripPunctuation(tt.in); got != tt.want {
+			t.Errorf("StripPunctuation(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDistances(t *testing.T) {
+	tests := []struct {
+		a, b        string
+		levenshtein int
+		osa         int
+	}{
+		{"", "", 0, 0},
+		{"", "abc", 3, 3},
+		{"kitten", "sitting", 3, 3},
+		{"the", "teh", 2, 1},
+		{"recieve", "receive", 2, 1},
+		{"café", "cafe", 1, 1},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.levenshtein {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.levenshtein)
+		}
+		if got := OSADistance(tt.a, tt.b); got != tt.osa {
+			t.Errorf("OSADistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.osa)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"abc", "abc", 1},
+		{"abc", "xyz", 0},
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"i attack the goblin with my sword", "i attack the goblin with my swords", 1 - 1.0/34},
+	}
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Similarity(%q, %q) = %.4f, want %.4f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestWordNGrams(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want []string
+	}{
+		{"", 2, nil},
+		{"attack", 2, []string{"attack"}},
+		{"i attack now", 2, []string{"i attack", "attack now"}},
+		{"go go go", 2, []string{"go go"}},
+	}
+	for _, tt := range tests {
+		got := WordNGrams(tt.s, tt.n)
+		if len(got) != len(tt.want) {
+			t.Errorf("WordNGrams(%q, %d) = %v, want %v", tt.s, tt.n, got, tt.want)
+			continue
+		}
+		for _, g := range tt.want {
+			if !got[g] {
+				t.Errorf("WordNGrams(%q, %d) = %v, missing %q", tt.s, tt.n, got, g)
+			}
+		}
+	}
+}
+
+func TestNGramOverlap(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "i attack", 0},
+		{"i attack the goblin", "i attack the goblin", 1},
+		{"i attack the goblin with my sword", "i attack the goblin with my swords", 5.0 / 6},
+		{"i attack the goblin with my sword", "i attack the goblin with my sword and shield", 6.0 / 8},
+		// A short opener shared with a longer sentence is measured
+		// against the longer one
+		{"i attack", "i attack the goblin with my sword", 1.0 / 6},
+		{"i attack the goblin with my sword", "i swing my sword at the goblin", 2.0 / 6},
+	}
+	for _, tt := range tests {
+		if got := NGramOverlap(tt.a, tt.b, 2); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("NGramOverlap(%q, %q) = %.4f, want %.4f", tt.a, tt.b, got, tt.want)
+		}
+		if got := NGramOverlap(tt.b, tt.a, 2); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("NGramOverlap(%q, %q) = %.4f, want %.4f", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
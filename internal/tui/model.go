@@ -43,6 +43,12 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.state = newState
 			return m, newState.Init(m.ctx)
 		}
+		if keyMsg.String() == states.HistoryKey {
+			if history, ok := states.OpenHistory(m.state, m.ctx); ok {
+				m.state = history
+				return m, history.Init(m.ctx)
+			}
+		}
 	}
 
 	// Delegate Update to the current state
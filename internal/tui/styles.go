@@ -1,9 +0,0 @@
-package tui
-
-import "github.com/charmbracelet/lipgloss"
-
-var (
-    GreyStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("204"))
-    TypedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("111"))
-    CursorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("222"))
-)
@@ -0,0 +1,179 @@
+package typing
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/erwaen/type-glish/internal/targets"
+)
+
+// MinPhraseWords keeps phrases long enough for a meaningful WPM: a
+// two-letter phrase typed in a blink would max out a quick strike
+const MinPhraseWords = 4
+
+// Phrases are typing targets, used with the startup targets
+var Phrases = []string{
+	"The goblin hides behind the old oak tree.",
+	"I raise my shield and step forward.",
+	"Words have power in the Kingdom of Lexicon.",
+	"The spider weaves a web of tangled clauses.",
+	"A cold wind blows through the ancient crypt.",
+	"My sword shines brighter with every good sentence.",
+	"The wizard whispers a spell in perfect grammar.",
+	"We cross the bridge before the storm arrives.",
+	"The troll laughs, but I do not give up.",
+	"Every careful word brings me closer to victory.",
+}
+
+// Session tracks the player retyping a target phrase
+type Session struct {
+	Target     []rune
+	Typed      []rune
+	Keystrokes int  // Every character typed, including later corrected ones
+	Errors     int  // Characters typed that did not match the target
+	Pasted     bool // Some of the text arrived in one paste, so it was not typed
+	Started    time.Time
+	Finished   time.Time
+}
+
+func NewSession(target string) *Session {
+	return &Session{Target: []rune(target)}
+}
+
+// Start starts the clock. Call it when the phrase is shown, so the time
+// to read it counts and the first keystroke is timed too.
+func (s *Session) Start(now time.Time) {
+	s.Started = now
+}
+
+// Paste records text that arrived all at once. It fills the phrase like
+// typing does, but the session no longer counts for WPM.
+func (s *Session) Paste(runes []rune, now time.Time) {
+	s.Pasted = true
+	for _, r := range runes {
+		s.Type(r, now)
+	}
+}
+
+// Type records a typed character. The clock starts here if Start was not
+// called.
+func (s *Session) Type(r rune, now time.Time) {
+	if s.Done() {
+		return
+	}
+	if s.Started.IsZero() {
+		s.Started = now
+	}
+
+	s.Keystrokes++
+	if r != s.Target[len(s.Typed)] {
+		s.Errors++
+	}
+	s.Typed = append(s.Typed, r)
+
+	if s.Done() {
+		s.Finished = now
+	}
+}
+
+// Backspace removes the last typed character
+func (s *Session) Backspace() {
+	if len(s.Typed) > 0 && !s.Done() {
+		s.Typed = s.Typed[:len(s.Typed)-1]
+	}
+}
+
+// Done reports whether the whole target has been typed
+func (s *Session) Done() bool {
+	return len(s.Typed) >= len(s.Target)
+}
+
+// CorrectChars returns how many typed characters match the target
+func (s *Session) CorrectChars() int {
+	correct := 0
+	for i, r := range s.Typed {
+		if r == s.Target[i] {
+			correct++
+		}
+	}
+	return correct
+}
+
+// Elapsed returns the typing time so far, or the total once done
+func (s *Session) Elapsed(now time.Time) time.Duration {
+	if s.Started.IsZero() {
+		return 0
+	}
+	if !s.Finished.IsZero() {
+		now = s.Finished
+	}
+	return now.Sub(s.Started)
+}
+
+// WPM returns the net words per minute, counting five correct
+// characters as one word. Pasted text and a session with no measured time
+// have no speed, so they return 0.
+func (s *Session) WPM(now time.Time) float64 {
+	if s.Pasted {
+		return 0
+	}
+	elapsed := s.Elapsed(now)
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.CorrectChars()) / 5 / elapsed.Minutes()
+}
+
+// Accuracy returns the share of keystrokes that were correct, from 0 to 1
+func (s *Session) Accuracy() float64 {
+	if s.Keystrokes == 0 {
+		return 1
+	}
+	return float64(s.Keystrokes-s.Errors) / float64(s.Keystrokes)
+}
+
+// RandomPhrases returns n distinct random phrases of at least
+// MinPhraseWords words, from Phrases and the startup targets
+func RandomPhrases(n int) []string {
+	phrases := append(startupPhrases(), Phrases...)
+	rand.Shuffle(len(phrases), func(i, j int) {
+		phrases[i], phrases[j] = phrases[j], phrases[i]
+	})
+	return phrases[:min(n, len(phrases))]
+}
+
+// startupPhrases turns the startup targets into typing phrases. Most of
+// them are a word or two, so short ones are joined in random order as
+// sentences of one phrase until it has MinPhraseWords words, like
+// "Let's roll. Power on. Go."
+func startupPhrases() []string {
+	examples := make([]string, len(targets.StartupExamples))
+	copy(examples, targets.StartupExamples)
+	rand.Shuffle(len(examples), func(i, j int) {
+		examples[i], examples[j] = examples[j], examples[i]
+	})
+
+	var phrases, parts []string
+	words := 0
+	for _, e := range examples {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if !strings.ContainsAny(e[len(e)-1:], ".!?") {
+			e += "."
+		}
+		parts = append(parts, e)
+		words += len(strings.Fields(e))
+		if words >= MinPhraseWords {
+			phrases = append(phrases, strings.Join(parts, " "))
+			parts, words = nil, 0
+		}
+	}
+	// Leftovers too short for a phrase of their own join the last one
+	if len(parts) > 0 && len(phrases) > 0 {
+		phrases[len(phrases)-1] += " " + strings.Join(parts, " ")
+	}
+	return phrases
+}
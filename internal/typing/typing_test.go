@@ -0,0 +1,113 @@
+package typing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erwaen/type-glish/internal/targets"
+)
+
+func TestStartupPhrases(t *testing.T) {
+	phrases := startupPhrases()
+	joined := strings.Join(phrases, " ")
+	for _, p := range phrases {
+		if n := len(strings.Fields(p)); n < MinPhraseWords {
+			t.Errorf("phrase %q has %d words, want at least %d", p, n, MinPhraseWords)
+		}
+	}
+	for _, e := range targets.StartupExamples {
+		if !strings.Contains(joined, strings.TrimSpace(e)) {
+			t.Errorf("startup target %q is not used", e)
+		}
+	}
+}
+
+func TestRandomPhrases(t *testing.T) {
+	phrases := RandomPhrases(1000)
+	if len(phrases) <= len(Phrases) {
+		t.Fatalf("got %d phrases, want the startup targets on top of the %d fixed ones", len(phrases), len(Phrases))
+	}
+	seen := make(map[string]bool)
+	for _, p := range phrases {
+		if seen[p] {
+			t.Errorf("phrase %q returned twice", p)
+		}
+		seen[p] = true
+	}
+	if got := RandomPhrases(3); len(got) != 3 {
+		t.Errorf("RandomPhrases(3) returned %d phrases", len(got))
+	}
+}
+
+func TestSessionWPM(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	target := "I raise my shield."
+
+	tests := []struct {
+		name     string
+		run      func(s *Session)
+		wpm      float64
+		accuracy float64
+	}{
+		{
+			name: "typed in 6 seconds",
+			run: func(s *Session) {
+				s.Start(start)
+				for _, r := range target {
+					s.Type(r, start.Add(6*time.Second))
+				}
+			},
+			wpm:      36,
+			accuracy: 1,
+		},
+		{
+			name: "mistakes count against accuracy and speed",
+			run: func(s *Session) {
+				s.Start(start)
+				s.Type('x', start)
+				s.Backspace()
+				for _, r := range target[:len(target)-1] {
+					s.Type(r, start)
+				}
+				s.Type('!', start.Add(6*time.Second))
+			},
+			wpm:      34,
+			accuracy: 17.0 / 19,
+		},
+		{
+			name: "pasted text has no speed",
+			run: func(s *Session) {
+				s.Start(start)
+				s.Paste([]rune(target), start.Add(time.Second))
+			},
+			wpm:      0,
+			accuracy: 1,
+		},
+		{
+			name: "no elapsed time",
+			run: func(s *Session) {
+				for _, r := range target {
+					s.Type(r, start)
+				}
+			},
+			wpm:      0,
+			accuracy: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSession(target)
+			tt.run(s)
+			if !s.Done() {
+				t.Fatal("session not done")
+			}
+			if got := s.WPM(start.Add(time.Hour)); got != tt.wpm {
+				t.Errorf("WPM = %v, want %v", got, tt.wpm)
+			}
+			if got := s.Accuracy(); got != tt.accuracy {
+				t.Errorf("Accuracy = %v, want %v", got, tt.accuracy)
+			}
+		})
+	}
+}
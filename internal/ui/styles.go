@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/erwaen/type-glish/internal/i18n"
 )
 
 var (
@@ -179,9 +180,9 @@ func RenderStatusBar(hp, maxHP, gold, xp int) string {
 		lipgloss.NewStyle().Foreground(ColorSubtext).Render(strings.Repeat("░", empty))
 
 	return fmt.Sprintf("%s %s %s  %s %s  %s %s",
-		labelStyle.Render("HP:"), bar, hpStyle.Render(fmt.Sprintf("%d/%d", hp, maxHP)),
-		labelStyle.Render("Gold:"), goldStyle.Render(fmt.Sprintf("%d", gold)),
-		labelStyle.Render("XP:"), xpStyle.Render(fmt.Sprintf("%d", xp)),
+		labelStyle.Render(i18n.T("status.hp")), bar, hpStyle.Render(fmt.Sprintf("%d/%d", hp, maxHP)),
+		labelStyle.Render(i18n.T("status.gold")), goldStyle.Render(fmt.Sprintf("%d", gold)),
+		labelStyle.Render(i18n.T("status.xp")), xpStyle.Render(fmt.Sprintf("%d", xp)),
 	)
 }
 
@@ -189,5 +190,66 @@ func RenderStatusBar(hp, maxHP, gold, xp int) string {
 func RenderCombatHeader(location, enemyName string) string {
 	loc := StyleLocation.Render(location)
 	enemy := StyleEnemyName.Render(enemyName)
-	return fmt.Sprintf("LOCATION: %s    ENEMY: %s", loc, enemy)
+	return i18n.T("status.combat_header", loc, enemy)
+}
+
+// RenderBanner renders a double-line box with a centered title and
+// left-aligned lines, growing to fit translated text
+func RenderBanner(title string, lines ...string) string {
+	inner := 39
+	for _, l := range append([]string{title}, lines...) {
+		inner = max(inner, lipgloss.Width(l)+6)
+	}
+
+	row := func(text string, center bool) string {
+		pad := inner - lipgloss.Width(text)
+		left := 3
+		if center {
+			left = pad / 2
+		}
+		return "    ║" + strings.Repeat(" ", left) + text + strings.Repeat(" ", pad-left) + "║\n"
+	}
+
+	banner := "\n    ╔" + strings.Repeat("═", inner) + "╗\n"
+	banner += row("", false)
+	banner += row(title, true)
+	banner += row("", false)
+	for _, l := range lines {
+		banner += row(l, false)
+	}
+	banner += row("", false)
+	banner += "    ╚" + strings.Repeat("═", inner) + "╝\n"
+	return banner
+}
+
+// Typing challenge styles
+var (
+	StyleTypingPending = lipgloss.NewStyle().Foreground(ColorSubtext)
+	StyleTypingCorrect = lipgloss.NewStyle().Foreground(lipgloss.Color("111"))
+	StyleTypingWrong   = lipgloss.NewStyle().Foreground(lipgloss.Color("204")).Underline(true)
+	StyleTypingCursor  = lipgloss.NewStyle().Foreground(lipgloss.Color("222")).Underline(true)
+)
+
+// RenderTypingTarget colors each character of the target phrase: typed
+// characters by correctness, the cursor, and the pending rest
+func RenderTypingTarget(target, typed []rune) string {
+	var sb strings.Builder
+	for i, r := range target {
+		ch := string(r)
+		switch {
+		case i < len(typed) && typed[i] == r:
+			sb.WriteString(StyleTypingCorrect.Render(ch))
+		case i < len(typed):
+			// Show the expected character so spaces are visible too
+			if r == ' ' {
+				ch = "·"
+			}
+			sb.WriteString(StyleTypingWrong.Render(ch))
+		case i == len(typed):
+			sb.WriteString(StyleTypingCursor.Render(ch))
+		default:
+			sb.WriteString(StyleTypingPending.Render(ch))
+		}
+	}
+	return sb.String()
 }
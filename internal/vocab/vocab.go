@@ -0,0 +1,78 @@
+package vocab
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/erwaen/type-glish/internal/config"
+)
+
+const bookFileName = "vocabulary.json"
+
+// Entry is a word the player collected, with the sentence it came from
+type Entry struct {
+	Word     string    `json:"word"`
+	Sentence string    `json:"sentence"`
+	Added    time.Time `json:"added"`
+}
+
+// Book is the persisted list of collected vocabulary
+type Book struct {
+	Entries []Entry `json:"entries"`
+}
+
+// LoadBook reads the vocabulary from the config directory, returning an
+// empty book if none has been saved yet
+func LoadBook() (*Book, error) {
+	path, err := config.GetDataPath(bookFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Book{}, nil
+		}
+		return nil, err
+	}
+
+	var book Book
+	if err := json.Unmarshal(data, &book); err != nil {
+		return nil, fmt.Errorf("failed to parse vocabulary: %w", err)
+	}
+	return &book, nil
+}
+
+// Save writes the vocabulary to the config directory
+func (b *Book) Save() error {
+	path, err := config.GetDataPath(bookFileName)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal vocabulary: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// Add collects a word, returning false if it was already in the book
+func (b *Book) Add(word, sentence string, now time.Time) bool {
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return false
+	}
+	for _, e := range b.Entries {
+		if strings.EqualFold(e.Word, word) {
+			return false
+		}
+	}
+	b.Entries = append(b.Entries, Entry{Word: word, Sentence: sentence, Added: now})
+	return true
+}